@@ -0,0 +1,67 @@
+package activity
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/errorcontext"
+	"github.com/TicketsBot/worker/bot/redis"
+	"sync"
+	"time"
+)
+
+// Event is a change to a ticket. Events are published by the functions in the logic package that make the change,
+// rather than by commands, so that changes made automatically (such as panel labels on open) are published too.
+type Event struct {
+	Type      redis.ActivityEvent
+	GuildId   uint64
+	TicketId  int
+	ChannelId uint64
+	// ActorId is the user who made the change. For automatic changes, such as auto-assignment, it is the bot.
+	ActorId uint64
+	// TargetId is the user the change was made to, such as the new claimer, or the member that was added
+	TargetId uint64
+	// Previous and Current are formatted descriptions of what changed, such as the old and new channel names
+	Previous string
+	Current  string
+	Reason   *string
+	Time     time.Time
+}
+
+type Handler func(worker *worker.Context, event Event) error
+
+var (
+	handlers   []Handler
+	handlersMu sync.RWMutex
+)
+
+// Subscribe registers a handler to be called for every event published. Handlers should be registered on startup.
+func Subscribe(handler Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+
+	handlers = append(handlers, handler)
+}
+
+// Publish passes the event to every handler in the background, so that publishers are not slowed down by them
+func Publish(worker *worker.Context, event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	handlersMu.RLock()
+	subscribed := make([]Handler, len(handlers))
+	copy(subscribed, handlers)
+	handlersMu.RUnlock()
+
+	go func() {
+		for _, handler := range subscribed {
+			if err := handler(worker, event); err != nil {
+				sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{
+					Guild:   event.GuildId,
+					User:    event.ActorId,
+					Channel: event.ChannelId,
+				})
+			}
+		}
+	}()
+}
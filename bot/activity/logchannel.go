@@ -0,0 +1,141 @@
+package activity
+
+import (
+	"fmt"
+	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/rest/request"
+)
+
+var eventTitles = map[redis.ActivityEvent]string{
+	redis.ActivityEventOpen:         "Ticket Opened",
+	redis.ActivityEventClaim:        "Ticket Claimed",
+	redis.ActivityEventUnclaim:      "Ticket Unclaimed",
+	redis.ActivityEventTransfer:     "Ticket Transferred",
+	redis.ActivityEventMemberAdd:    "Member Added",
+	redis.ActivityEventMemberRemove: "Member Removed",
+	redis.ActivityEventRename:       "Ticket Renamed",
+	redis.ActivityEventLabel:        "Labels Changed",
+	redis.ActivityEventCloseRequest: "Close Requested",
+	redis.ActivityEventReopen:       "Ticket Reopened",
+	redis.ActivityEventPanelSwitch:  "Panel Switched",
+}
+
+// The name of the field that TargetId is shown in
+var eventTargetNames = map[redis.ActivityEvent]string{
+	redis.ActivityEventOpen:         "Opened For",
+	redis.ActivityEventClaim:        "Claimed By",
+	redis.ActivityEventTransfer:     "Transferred To",
+	redis.ActivityEventMemberAdd:    "Member",
+	redis.ActivityEventMemberRemove: "Member",
+	redis.ActivityEventUnclaim:      "Previously Claimed By",
+	redis.ActivityEventCloseRequest: "Opener",
+}
+
+// The names of the fields that Previous and Current are shown in, if not "Before" and "After"
+var eventChangeNames = map[redis.ActivityEvent][2]string{
+	redis.ActivityEventCloseRequest: {"", "Closes Automatically"},
+}
+
+var eventColours = map[redis.ActivityEvent]customisation.Colour{
+	redis.ActivityEventUnclaim:      customisation.Orange,
+	redis.ActivityEventMemberRemove: customisation.Red,
+	redis.ActivityEventRename:       customisation.Blue,
+	redis.ActivityEventLabel:        customisation.Blue,
+	redis.ActivityEventCloseRequest: customisation.Orange,
+	redis.ActivityEventPanelSwitch:  customisation.Blue,
+}
+
+// PostToLogChannel sends an embed describing the event to the guild's activity log channel, if the guild has one and
+// the event type is enabled
+func PostToLogChannel(worker *worker.Context, event Event) error {
+	settings, err := redis.GetActivityLogSettings(event.GuildId)
+	if err != nil {
+		return err
+	}
+
+	if !settings.EventEnabled(event.Type) {
+		return nil
+	}
+
+	return SendLogEmbed(worker, settings.ChannelId, buildLogEmbed(event))
+}
+
+// SendLogEmbed sends the embed to a log channel. Errors caused by the channel being deleted, or the bot no longer being
+// able to send messages in it, are ignored, as there is nothing we can do about them.
+func SendLogEmbed(worker *worker.Context, channelId uint64, e *embed.Embed) error {
+	if _, err := worker.CreateMessageEmbed(channelId, e); err != nil {
+		if restError, ok := err.(request.RestError); ok && restError.IsClientError() {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func buildLogEmbed(event Event) *embed.Embed {
+	colour, ok := eventColours[event.Type]
+	if !ok {
+		colour = customisation.Green
+	}
+
+	title, ok := eventTitles[event.Type]
+	if !ok {
+		title = string(event.Type)
+	}
+
+	ticket := fmt.Sprintf("#%d", event.TicketId)
+	if event.ChannelId != 0 {
+		ticket = fmt.Sprintf("#%d (<#%d>)", event.TicketId, event.ChannelId)
+	}
+
+	e := embed.NewEmbed().
+		SetTitle(title).
+		SetColor(customisation.GetColourOrDefault(event.GuildId, colour)).
+		SetTimestamp(event.Time).
+		AddField("Ticket", ticket, true)
+
+	if event.ActorId != 0 {
+		e.AddField("By", fmt.Sprintf("<@%d>", event.ActorId), true)
+	}
+
+	if event.TargetId != 0 {
+		name, ok := eventTargetNames[event.Type]
+		if !ok {
+			name = "User"
+		}
+
+		e.AddField(name, fmt.Sprintf("<@%d>", event.TargetId), true)
+	}
+
+	changeNames, ok := eventChangeNames[event.Type]
+	if !ok {
+		changeNames = [2]string{"Before", "After"}
+	}
+
+	if event.Previous != "" {
+		e.AddField(changeNames[0], truncateField(event.Previous), true)
+	}
+
+	if event.Current != "" {
+		e.AddField(changeNames[1], truncateField(event.Current), true)
+	}
+
+	if event.Reason != nil && *event.Reason != "" {
+		e.AddField("Reason", truncateField(*event.Reason), false)
+	}
+
+	return e
+}
+
+func truncateField(s string) string {
+	if len(s) > 1024 {
+		return s[:1021] + "..."
+	}
+
+	return s
+}
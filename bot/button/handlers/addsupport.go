@@ -8,16 +8,11 @@ import (
 	"github.com/TicketsBot/worker/bot/button/registry/matcher"
 	"github.com/TicketsBot/worker/bot/command"
 	"github.com/TicketsBot/worker/bot/command/context"
-	cmdregistry "github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/logic"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
-	"github.com/rxdn/gdl/objects/channel"
-	"github.com/rxdn/gdl/permission"
-	"github.com/rxdn/gdl/rest"
-	"github.com/rxdn/gdl/rest/request"
 	"regexp"
 	"strconv"
 	"strings"
@@ -114,74 +109,7 @@ func (h *AddSupportHandler) Execute(ctx *context.ButtonContext) {
 	e := utils.BuildEmbed(ctx, customisation.Green, i18n.TitleAddSupport, i18n.MessageAddSupportSuccess, nil)
 	ctx.Edit(command.NewEphemeralEmbedMessageResponse(e))
 
-	updateChannelPermissions(ctx, id, mentionableType)
-}
-
-func updateChannelPermissions(ctx cmdregistry.CommandContext, id uint64, mentionableType context.MentionableType) {
-	settings, err := dbclient.Client.Settings.Get(ctx.GuildId())
-	if err != nil {
-		ctx.HandleError(err)
-		return
-	}
-
-	if settings.TicketNotificationChannel != nil {
-		// Add user / role to thread notification channel
-		_ = ctx.Worker().EditChannelPermissions(*settings.TicketNotificationChannel, channel.PermissionOverwrite{
-			Id:    id,
-			Type:  mentionableType.OverwriteType(),
-			Allow: permission.BuildPermissions(permission.ViewChannel, permission.UseApplicationCommands, permission.ReadMessageHistory),
-			Deny:  0,
-		})
-	}
-
-	openTickets, err := dbclient.Client.Tickets.GetGuildOpenTicketsExcludeThreads(ctx.GuildId())
-	if err != nil {
+	if err := logic.GrantStaffAccess(ctx, id, mentionableType.OverwriteType()); err != nil {
 		ctx.HandleError(err)
-		return
-	}
-
-	// Update permissions for existing tickets
-	for _, ticket := range openTickets {
-		if ticket.ChannelId == nil || ticket.IsThread {
-			continue
-		}
-
-		ch, err := ctx.Worker().GetChannel(*ticket.ChannelId)
-		if err != nil {
-			// Check if the channel has been deleted
-			if restError, ok := err.(request.RestError); ok {
-				if restError.StatusCode == 404 {
-					if err := dbclient.Client.Tickets.CloseByChannel(*ticket.ChannelId); err != nil {
-						ctx.HandleError(err)
-						return
-					}
-
-					continue
-				} else if restError.StatusCode == 403 {
-					break
-				}
-			} else {
-				ctx.HandleError(err)
-				return
-			}
-		}
-
-		// Apply overwrites to existing channels
-		overwrites := append(ch.PermissionOverwrites, channel.PermissionOverwrite{
-			Id:    id,
-			Type:  mentionableType.OverwriteType(),
-			Allow: permission.BuildPermissions(logic.StandardPermissions[:]...),
-			Deny:  0,
-		})
-
-		data := rest.ModifyChannelData{
-			PermissionOverwrites: overwrites,
-			Position:             ch.Position,
-		}
-
-		if _, err = ctx.Worker().ModifyChannel(*ticket.ChannelId, data); err != nil {
-			ctx.HandleError(err)
-			return
-		}
 	}
 }
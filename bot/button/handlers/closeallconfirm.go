@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	permcache "github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/button/registry"
+	"github.com/TicketsBot/worker/bot/button/registry/matcher"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/context"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"strings"
+)
+
+type CloseAllConfirmHandler struct{}
+
+func (h *CloseAllConfirmHandler) Matcher() matcher.Matcher {
+	return matcher.NewFuncMatcher(func(customId string) bool {
+		return strings.HasPrefix(customId, "closeall_confirm_")
+	})
+}
+
+func (h *CloseAllConfirmHandler) Properties() registry.Properties {
+	return registry.Properties{
+		Flags: registry.SumFlags(registry.GuildAllowed, registry.CanEdit),
+	}
+}
+
+func (h *CloseAllConfirmHandler) Execute(ctx *context.ButtonContext) {
+	permLevel, err := ctx.UserPermissionLevel()
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if permLevel < permcache.Admin {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageNoPermission)
+		return
+	}
+
+	id := strings.TrimPrefix(ctx.InteractionData.CustomId, "closeall_confirm_")
+
+	filter, ok, err := redis.GetBulkCloseFilter(ctx.GuildId(), id)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageCloseAllExpired)
+		return
+	}
+
+	lockToken, locked, err := redis.TakeBulkCloseLock(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !locked {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageCloseAllAlreadyRunning)
+		return
+	}
+
+	// Prevent the button from being pressed twice
+	if err := redis.DeleteBulkCloseFilter(ctx.GuildId(), id); err != nil {
+		ctx.HandleError(err)
+		_ = redis.ReleaseBulkCloseLock(ctx.GuildId(), lockToken)
+		return
+	}
+
+	// Tickets may have been opened or closed since the preview was generated
+	tickets, err := logic.FindBulkCloseTickets(ctx.GuildId(), filter)
+	if err != nil {
+		ctx.HandleError(err)
+		_ = redis.ReleaseBulkCloseLock(ctx.GuildId(), lockToken)
+		return
+	}
+
+	if len(tickets) == 0 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageCloseAllNoTickets)
+		_ = redis.ReleaseBulkCloseLock(ctx.GuildId(), lockToken)
+		return
+	}
+
+	ticketIds := make([]int, len(tickets))
+	for i, ticket := range tickets {
+		ticketIds[i] = ticket.Id
+	}
+
+	// The interaction token expires before large jobs finish, so progress is reported in a regular message
+	progressEmbed := utils.BuildEmbed(ctx, customisation.Orange, i18n.TitleCloseAll, i18n.MessageCloseAllProgress, nil, 0, len(ticketIds))
+	progressMessage, err := ctx.Worker().CreateMessageEmbed(ctx.ChannelId(), progressEmbed)
+	if err != nil {
+		ctx.HandleError(err)
+		_ = redis.ReleaseBulkCloseLock(ctx.GuildId(), lockToken)
+		return
+	}
+
+	job := redis.BulkCloseJob{
+		GuildId:           ctx.GuildId(),
+		ChannelId:         ctx.ChannelId(),
+		UserId:            ctx.UserId(),
+		ProgressMessageId: progressMessage.Id,
+		TicketIds:         ticketIds,
+		Token:             lockToken,
+	}
+
+	if err := redis.QueueBulkCloseJob(job); err != nil {
+		ctx.HandleError(err)
+		_ = redis.ReleaseBulkCloseLock(ctx.GuildId(), lockToken)
+		return
+	}
+
+	e := utils.BuildEmbed(ctx, customisation.Green, i18n.TitleCloseAll, i18n.MessageCloseAllStarted, nil, len(ticketIds))
+	ctx.Edit(command.NewEphemeralEmbedMessageResponse(e))
+}
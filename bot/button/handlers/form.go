@@ -1,15 +1,22 @@
 package handlers
 
 import (
+	"fmt"
 	"github.com/TicketsBot/common/sentry"
 	"github.com/TicketsBot/database"
 	"github.com/TicketsBot/worker/bot/button/registry"
 	"github.com/TicketsBot/worker/bot/button/registry/matcher"
+	"github.com/TicketsBot/worker/bot/command"
 	"github.com/TicketsBot/worker/bot/command/context"
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction/component"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -17,7 +24,7 @@ type FormHandler struct{}
 
 func (h *FormHandler) Matcher() matcher.Matcher {
 	return matcher.NewFuncMatcher(func(customId string) bool {
-		return strings.HasPrefix(customId, "form_")
+		return strings.HasPrefix(customId, "form_") || strings.HasPrefix(customId, "formstep_")
 	})
 }
 
@@ -27,11 +34,26 @@ func (h *FormHandler) Properties() registry.Properties {
 	}
 }
 
+var formStepPattern = regexp.MustCompile(`^formstep_(\d+)_(.+)$`)
+
 func (h *FormHandler) Execute(ctx *context.ModalContext) {
 	data := ctx.Interaction.Data
-	customId := strings.TrimPrefix(data.CustomId, "form_") // get the custom id that is used in the database
 
-	// Form IDs aren't unique to a panel, so we submit the modal with a custom id of `form_panelcustomid`
+	// Form IDs aren't unique to a panel, so we submit the modal with a custom id of `form_panelcustomid`. Pages after
+	// the first are submitted with `formstep_page_panelcustomid`.
+	var customId string
+	var page int
+	if groups := formStepPattern.FindStringSubmatch(data.CustomId); len(groups) == 3 {
+		var err error
+		if page, err = strconv.Atoi(groups[1]); err != nil {
+			return
+		}
+
+		customId = groups[2]
+	} else {
+		customId = strings.TrimPrefix(data.CustomId, "form_") // get the custom id that is used in the database
+	}
+
 	panel, ok, err := dbclient.Client.Panel.GetByCustomId(ctx.GuildId(), customId)
 	if err != nil {
 		sentry.Error(err) // TODO: Proper context
@@ -56,6 +78,16 @@ func (h *FormHandler) Execute(ctx *context.ModalContext) {
 			return
 		}
 
+		eligible, err := logic.CheckPanelEligibility(ctx, panel)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if !eligible {
+			return
+		}
+
 		inputs, err := dbclient.Client.FormInput.GetAllInputsByCustomId(ctx.GuildId())
 		if err != nil {
 			ctx.HandleError(err)
@@ -63,34 +95,98 @@ func (h *FormHandler) Execute(ctx *context.ModalContext) {
 		}
 
 		formAnswers := make(map[database.FormInput]string)
+		rawAnswers := make(map[string]string)
 		for _, actionRow := range data.Components {
 			for _, input := range actionRow.Components {
 				questionData, ok := inputs[input.CustomId]
 				if ok { // If form has changed, we can skip
 					formAnswers[questionData] = input.Value
+					rawAnswers[input.CustomId] = input.Value
 				}
 			}
 		}
 
+		// Answers to previous pages, and any rejected answers to this page
+		previousAnswers, err := redis.GetFormAnswers(ctx.GuildId(), ctx.UserId(), panel.PanelId)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if page > 0 && previousAnswers == nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageFormValidationExpired)
+			return
+		}
+
+		if previousAnswers == nil {
+			previousAnswers = make(map[string]string)
+		}
+
+		for customId, answer := range rawAnswers {
+			previousAnswers[customId] = answer
+		}
+
 		// Validate user input
-		for question, answer := range formAnswers {
-			if !question.Required {
-				continue
+		var validation map[int]redis.FormInputValidation
+		var pageCount int
+		if panel.FormId != nil {
+			validation, err = redis.GetFormValidation(*panel.FormId)
+			if err != nil {
+				ctx.HandleError(err)
+				return
 			}
 
-			// Check that users have not just pressed newline or space
-			isValid := false
-			for _, c := range answer {
-				if c != rune(' ') && c != rune('\n') {
-					isValid = true
-					break
-				}
+			formInputs, err := dbclient.Client.FormInput.GetInputs(*panel.FormId)
+			if err != nil {
+				ctx.HandleError(err)
+				return
 			}
 
-			if !isValid {
-				ctx.Reply(customisation.Red, i18n.Error, i18n.MessageFormMissingInput, question.Label)
+			pageCount = formPageCount(formInputs)
+		}
+
+		if failures := logic.ValidateFormAnswers(ctx, formAnswers, validation); len(failures) > 0 {
+			// Modals can't be sent in response to a modal, so let the user reopen the form with their answers prefilled
+			if err := redis.StoreFormAnswers(ctx.GuildId(), ctx.UserId(), panel.PanelId, previousAnswers); err != nil {
+				ctx.HandleError(err)
+				return
+			}
+
+			for i, failure := range failures {
+				failures[i] = fmt.Sprintf("• %s", failure)
+			}
+
+			e := utils.BuildEmbedRaw(ctx.GetColour(customisation.Red), ctx.GetMessage(i18n.Error), strings.Join(failures, "\n"), nil, ctx.PremiumTier())
+			components := utils.Slice(component.BuildActionRow(buildFormPageButton(panel, page, ctx.GetMessage(i18n.MessageFormValidationRetry))))
+
+			_, _ = ctx.ReplyWith(command.NewEphemeralEmbedMessageResponseWithComponents(e, components))
+			return
+		}
+
+		// Keep the answers until the last page has been submitted
+		if page+1 < pageCount {
+			if err := redis.StoreFormAnswers(ctx.GuildId(), ctx.UserId(), panel.PanelId, previousAnswers); err != nil {
+				ctx.HandleError(err)
 				return
 			}
+
+			content := ctx.GetMessage(i18n.MessageFormContinue, page+1, pageCount)
+			e := utils.BuildEmbedRaw(ctx.GetColour(customisation.Green), panel.Title, content, nil, ctx.PremiumTier())
+			components := utils.Slice(component.BuildActionRow(buildFormPageButton(panel, page+1, ctx.GetMessage(i18n.MessageFormContinueButton))))
+
+			_, _ = ctx.ReplyWith(command.NewEphemeralEmbedMessageResponseWithComponents(e, components))
+			return
+		}
+
+		if err := redis.DeleteFormAnswers(ctx.GuildId(), ctx.UserId(), panel.PanelId); err != nil {
+			ctx.HandleError(err)
+		}
+
+		// Combine the answers from all pages
+		for customId, answer := range previousAnswers {
+			if questionData, ok := inputs[customId]; ok && panel.FormId != nil && questionData.FormId == *panel.FormId {
+				formAnswers[questionData] = answer
+			}
 		}
 
 		ctx.Defer()
@@ -99,3 +195,11 @@ func (h *FormHandler) Execute(ctx *context.ModalContext) {
 		return
 	}
 }
+
+func buildFormPageButton(panel database.Panel, page int, label string) component.Component {
+	return component.BuildButton(component.Button{
+		Label:    label,
+		CustomId: fmt.Sprintf("formpage_%d_%s", page, panel.CustomId),
+		Style:    component.ButtonStylePrimary,
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/TicketsBot/worker/bot/button/registry"
+	"github.com/TicketsBot/worker/bot/button/registry/matcher"
+	"github.com/TicketsBot/worker/bot/command/context"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type FormPageHandler struct{}
+
+func (h *FormPageHandler) Matcher() matcher.Matcher {
+	return matcher.NewFuncMatcher(func(customId string) bool {
+		return strings.HasPrefix(customId, "formpage_")
+	})
+}
+
+func (h *FormPageHandler) Properties() registry.Properties {
+	return registry.Properties{
+		Flags: registry.SumFlags(registry.GuildAllowed),
+	}
+}
+
+var formPagePattern = regexp.MustCompile(`^formpage_(\d+)_(.+)$`)
+
+// Execute opens a page of the form, prefilled with the user's answers so far. Used to move on to the next page, or to
+// correct answers that failed validation.
+func (h *FormPageHandler) Execute(ctx *context.ButtonContext) {
+	groups := formPagePattern.FindStringSubmatch(ctx.InteractionData.CustomId)
+	if len(groups) < 3 {
+		return
+	}
+
+	page, err := strconv.Atoi(groups[1])
+	if err != nil {
+		return
+	}
+
+	customId := groups[2]
+
+	panel, ok, err := dbclient.Client.Panel.GetByCustomId(ctx.GuildId(), customId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok || panel.GuildId != ctx.GuildId() || panel.FormId == nil {
+		return
+	}
+
+	answers, err := redis.GetFormAnswers(ctx.GuildId(), ctx.UserId(), panel.PanelId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if answers == nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageFormValidationExpired)
+		return
+	}
+
+	form, ok, err := dbclient.Client.Forms.Get(*panel.FormId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok {
+		ctx.HandleError(errors.New("Form not found"))
+		return
+	}
+
+	inputs, err := dbclient.Client.FormInput.GetInputs(form.Id)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	validation, err := redis.GetFormValidation(form.Id)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if page >= formPageCount(inputs) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageFormValidationExpired)
+		return
+	}
+
+	ctx.Modal(buildForm(panel, form, inputs, page, validation, answers))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
 	"github.com/TicketsBot/worker/i18n"
 )
 
@@ -57,6 +58,16 @@ func (h *MultiPanelHandler) Execute(ctx *context.SelectMenuContext) {
 			return
 		}
 
+		eligible, err := logic.CheckPanelEligibility(ctx, panel)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if !eligible {
+			return
+		}
+
 		if panel.FormId == nil {
 			_, _ = logic.OpenTicket(ctx, &panel, panel.Title, nil)
 		} else {
@@ -80,7 +91,25 @@ func (h *MultiPanelHandler) Execute(ctx *context.SelectMenuContext) {
 			if len(inputs) == 0 { // Don't open a blank form
 				_, _ = logic.OpenTicket(ctx, &panel, panel.Title, nil)
 			} else {
-				modal := buildForm(panel, form, inputs)
+				// Also checked when the ticket is opened, but checked here too so that users are not told they cannot open
+				// a ticket only after filling in every page of the form
+				withinLimits, err := logic.CheckTicketLimits(ctx, &panel)
+				if err != nil {
+					ctx.HandleError(err)
+					return
+				}
+
+				if !withinLimits {
+					return
+				}
+
+				validation, err := redis.GetFormValidation(form.Id)
+				if err != nil {
+					ctx.HandleError(err)
+					return
+				}
+
+				modal := buildForm(panel, form, inputs, 0, validation, nil)
 				ctx.Modal(modal)
 			}
 		}
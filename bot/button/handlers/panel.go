@@ -12,6 +12,7 @@ import (
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/interaction"
@@ -55,6 +56,16 @@ func (h *PanelHandler) Execute(ctx *context.ButtonContext) {
 			return
 		}
 
+		eligible, err := logic.CheckPanelEligibility(ctx, panel)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if !eligible {
+			return
+		}
+
 		if panel.FormId == nil {
 			_, _ = logic.OpenTicket(ctx, &panel, panel.Title, nil)
 		} else {
@@ -78,7 +89,25 @@ func (h *PanelHandler) Execute(ctx *context.ButtonContext) {
 			if len(inputs) == 0 { // Don't open a blank form
 				_, _ = logic.OpenTicket(ctx, &panel, panel.Title, nil)
 			} else {
-				modal := buildForm(panel, form, inputs)
+				// Also checked when the ticket is opened, but checked here too so that users are not told they cannot open
+				// a ticket only after filling in every page of the form
+				withinLimits, err := logic.CheckTicketLimits(ctx, &panel)
+				if err != nil {
+					ctx.HandleError(err)
+					return
+				}
+
+				if !withinLimits {
+					return
+				}
+
+				validation, err := redis.GetFormValidation(form.Id)
+				if err != nil {
+					ctx.HandleError(err)
+					return
+				}
+
+				modal := buildForm(panel, form, inputs, 0, validation, nil)
 				ctx.Modal(modal)
 			}
 		}
@@ -87,16 +116,48 @@ func (h *PanelHandler) Execute(ctx *context.ButtonContext) {
 	}
 }
 
-func buildForm(panel database.Panel, form database.Form, inputs []database.FormInput) button.ResponseModal {
+// Discord allows at most 5 inputs per modal
+const formPageSize = 5
+
+func formPageCount(inputs []database.FormInput) int {
+	return (len(inputs) + formPageSize - 1) / formPageSize
+}
+
+// buildForm builds the modal for a page of the form (starting from 0), prefilled with any previous answers
+func buildForm(panel database.Panel, form database.Form, inputs []database.FormInput, page int, validation map[int]redis.FormInputValidation, answers map[string]string) button.ResponseModal {
+	pageCount := formPageCount(inputs)
+
+	start := page * formPageSize
+	end := start + formPageSize
+	if end > len(inputs) {
+		end = len(inputs)
+	}
+
+	inputs = inputs[start:end]
+
 	components := make([]component.Component, len(inputs))
 	for i, input := range inputs {
-		style := component.TextStyleTypes(input.Style) // wrap
+		maxLength := uint32(logic.FormInputMaxLength(input))
+
+		var minLength *uint32
+		if rules, ok := validation[input.Id]; ok {
+			if rules.MinLength != nil && *rules.MinLength > 0 {
+				minLength = utils.Ptr(uint32(*rules.MinLength))
+			}
 
-		var maxLength uint32
-		if style == component.TextStyleShort {
-			maxLength = 255
-		} else if style == component.TextStyleParagraph {
-			maxLength = 1024 // Max embed field value
+			if rules.MaxLength != nil && *rules.MaxLength < int(maxLength) {
+				maxLength = uint32(*rules.MaxLength)
+			}
+		}
+
+		// Prefill previous answers, if the user is correcting them. Discord rejects values longer than the max length.
+		var value *string
+		if answer, ok := answers[input.CustomId]; ok && answer != "" {
+			if runes := []rune(answer); len(runes) > int(maxLength) {
+				answer = string(runes[:maxLength])
+			}
+
+			value = &answer
 		}
 
 		components[i] = component.BuildActionRow(component.BuildInputText(component.InputText{
@@ -104,16 +165,28 @@ func buildForm(panel database.Panel, form database.Form, inputs []database.FormI
 			CustomId:    input.CustomId,
 			Label:       input.Label,
 			Placeholder: input.Placeholder,
-			MinLength:   nil,
+			MinLength:   minLength,
 			MaxLength:   &maxLength,
 			Required:    utils.Ptr(input.Required),
+			Value:       value,
 		}))
 	}
 
+	customId := fmt.Sprintf("form_%s", panel.CustomId)
+	title := form.Title
+	if page > 0 {
+		customId = fmt.Sprintf("formstep_%d_%s", page, panel.CustomId)
+	}
+
+	if pageCount > 1 {
+		suffix := fmt.Sprintf(" (%d/%d)", page+1, pageCount)
+		title = utils.StringMax(title, 45-len(suffix)) + suffix // Max modal title length
+	}
+
 	return button.ResponseModal{
 		Data: interaction.ModalResponseData{
-			CustomId:   fmt.Sprintf("form_%s", panel.CustomId),
-			Title:      form.Title,
+			CustomId:   customId,
+			Title:      title,
 			Components: components,
 		},
 	}
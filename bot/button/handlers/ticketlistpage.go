@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"github.com/TicketsBot/worker/bot/button/registry"
+	"github.com/TicketsBot/worker/bot/button/registry/matcher"
+	"github.com/TicketsBot/worker/bot/command/context"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type TicketListPageHandler struct{}
+
+func (h *TicketListPageHandler) Matcher() matcher.Matcher {
+	return matcher.NewFuncMatcher(func(customId string) bool {
+		return strings.HasPrefix(customId, "ticketlist_")
+	})
+}
+
+func (h *TicketListPageHandler) Properties() registry.Properties {
+	return registry.Properties{
+		Flags: registry.SumFlags(registry.GuildAllowed, registry.CanEdit),
+	}
+}
+
+var ticketListPagePattern = regexp.MustCompile(`ticketlist_([0-9a-f-]+)_(-?\d+)`)
+
+func (h *TicketListPageHandler) Execute(ctx *context.ButtonContext) {
+	groups := ticketListPagePattern.FindStringSubmatch(ctx.InteractionData.CustomId)
+	if len(groups) < 3 {
+		return
+	}
+
+	filterId := groups[1]
+
+	page, err := strconv.Atoi(groups[2])
+	if err != nil || page < 0 {
+		return
+	}
+
+	filter, ok, err := redis.GetTicketListFilter(ctx.GuildId(), filterId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTicketListExpired)
+		return
+	}
+
+	res, err := logic.BuildTicketListResponse(ctx, filterId, filter, page)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Edit(res)
+}
@@ -55,13 +55,16 @@ func (m *ComponentInteractionManager) RegisterCommands() {
 		new(handlers.CloseWithReasonModalHandler),
 		new(handlers.ClaimHandler),
 		new(handlers.CloseConfirmHandler),
+		new(handlers.CloseAllConfirmHandler),
 		new(handlers.CloseRequestAcceptHandler),
 		new(handlers.CloseRequestDenyHandler),
+		new(handlers.FormPageHandler),
 		new(handlers.JoinThreadHandler),
 		new(handlers.PanelHandler),
 		new(handlers.PremiumCheckAgain),
 		new(handlers.PremiumKeyButtonHandler),
 		new(handlers.RateHandler),
+		new(handlers.TicketListPageHandler),
 		new(handlers.ViewStaffHandler),
 	)
 
@@ -90,6 +90,10 @@ func (ctx *SlashCommandContext) IsInteraction() bool {
 	return true
 }
 
+func (ctx *SlashCommandContext) InteractionToken() string {
+	return ctx.Interaction.Token
+}
+
 func (ctx *SlashCommandContext) ToErrorContext() errorcontext.WorkerErrorContext {
 	return errorcontext.WorkerErrorContext{
 		Guild:   ctx.GuildId(),
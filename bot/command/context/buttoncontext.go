@@ -83,6 +83,10 @@ func (ctx *ButtonContext) IsInteraction() bool {
 	return true
 }
 
+func (ctx *ButtonContext) InteractionToken() string {
+	return ctx.Interaction.Token
+}
+
 func (ctx *ButtonContext) ToErrorContext() errorcontext.WorkerErrorContext {
 	return errorcontext.WorkerErrorContext{
 		Guild:   ctx.GuildId(),
@@ -106,6 +106,10 @@ func (ctx *ModalContext) IsInteraction() bool {
 	return true
 }
 
+func (ctx *ModalContext) InteractionToken() string {
+	return ctx.Interaction.Token
+}
+
 func (ctx *ModalContext) ToErrorContext() errorcontext.WorkerErrorContext {
 	return errorcontext.WorkerErrorContext{
 		Guild:   ctx.GuildId(),
@@ -83,6 +83,10 @@ func (ctx *SelectMenuContext) IsInteraction() bool {
 	return true
 }
 
+func (ctx *SelectMenuContext) InteractionToken() string {
+	return ctx.Interaction.Token
+}
+
 func (ctx *SelectMenuContext) ToErrorContext() errorcontext.WorkerErrorContext {
 	return errorcontext.WorkerErrorContext{
 		Guild:   ctx.GuildId(),
@@ -28,6 +28,8 @@ func (AdminCommand) Properties() registry.Properties {
 			AdminGenPremiumCommand{},
 			AdminGetOwnerCommand{},
 			AdminRecacheCommand{},
+			AdminReplayTranscriptCommand{},
+			AdminTranscriptOutboxCommand{},
 			AdminUnblacklistCommand{},
 			AdminWhitelabelDataCommand{},
 		},
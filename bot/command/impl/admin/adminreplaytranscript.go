@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strconv"
+)
+
+type AdminReplayTranscriptCommand struct {
+}
+
+func (AdminReplayTranscriptCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "replaytranscript",
+		Description:     i18n.HelpAdminReplayTranscript,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Everyone,
+		Category:        command.Settings,
+		HelperOnly:      true,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("guild_id", "ID of the guild the ticket is in", interaction.OptionTypeString, i18n.MessageInvalidArgument),
+			command.NewRequiredArgument("ticket_id", "ID of the ticket to retry the transcript upload for", interaction.OptionTypeInteger, i18n.MessageInvalidArgument),
+		),
+	}
+}
+
+func (c AdminReplayTranscriptCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (AdminReplayTranscriptCommand) Execute(ctx registry.CommandContext, raw string, ticketId int) {
+	guildId, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		ctx.ReplyRaw(customisation.Red, ctx.GetMessage(i18n.Error), "Invalid guild ID provided")
+		return
+	}
+
+	entry, ok, err := redis.ClaimTranscriptOutbox(guildId, ticketId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok {
+		ctx.ReplyRaw(customisation.Red, ctx.GetMessage(i18n.Error), "There is no failed upload for this ticket, or it is already being retried")
+		return
+	}
+
+	if err := logic.RetryTranscriptUpload(entry); err != nil {
+		ctx.ReplyRaw(customisation.Red, ctx.GetMessage(i18n.Error), fmt.Sprintf("Upload failed again, and has been rescheduled: `%s`", err.Error()))
+		return
+	}
+
+	ctx.ReplyRaw(customisation.Green, ctx.GetMessage(i18n.Admin), fmt.Sprintf("The transcript for ticket #%d has been uploaded", ticketId))
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strconv"
+	"strings"
+)
+
+type AdminTranscriptOutboxCommand struct {
+}
+
+func (AdminTranscriptOutboxCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "transcriptoutbox",
+		Description:     i18n.HelpAdminTranscriptOutbox,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Everyone,
+		Category:        command.Settings,
+		HelperOnly:      true,
+		Arguments: command.Arguments(
+			command.NewOptionalArgument("guild_id", "Only show uploads for this guild", interaction.OptionTypeString, i18n.MessageInvalidArgument),
+		),
+	}
+}
+
+func (c AdminTranscriptOutboxCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (AdminTranscriptOutboxCommand) Execute(ctx registry.CommandContext, providedGuildId *string) {
+	var guildId *uint64
+	if providedGuildId != nil {
+		parsed, err := strconv.ParseUint(*providedGuildId, 10, 64)
+		if err != nil {
+			ctx.ReplyRaw(customisation.Red, ctx.GetMessage(i18n.Error), "Invalid guild ID provided")
+			return
+		}
+
+		guildId = &parsed
+	}
+
+	entries, err := redis.ListTranscriptOutbox(guildId, 15)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if len(entries) == 0 {
+		ctx.ReplyRaw(customisation.Green, ctx.GetMessage(i18n.Admin), "There are no pending transcript uploads")
+		return
+	}
+
+	var sb strings.Builder
+	for _, entry := range entries {
+		var next string
+		if entry.NextAttempt == nil {
+			next = "**not retrying**"
+		} else {
+			next = fmt.Sprintf("retrying <t:%d:R>", entry.NextAttempt.Unix())
+		}
+
+		lastError := entry.LastError
+		if len(lastError) > 100 {
+			lastError = lastError[:100] + "..."
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"`%d` ticket #%d: closed <t:%d:R>, %d attempts, %s\n> `%s`\n",
+			entry.GuildId, entry.TicketId, entry.CreatedAt.Unix(), entry.Attempts, next, strings.ReplaceAll(lastError, "`", "'"),
+		))
+	}
+
+	ctx.ReplyRaw(customisation.Green, ctx.GetMessage(i18n.Admin), strings.TrimSuffix(sb.String(), "\n"))
+}
@@ -0,0 +1,78 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"github.com/rxdn/gdl/rest/request"
+)
+
+type ActivityLogSetupCommand struct{}
+
+func (ActivityLogSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "activity-log",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewOptionalArgument("channel", "The channel that ticket activity should be logged to. Leave blank to stop logging", interaction.OptionTypeChannel, i18n.SetupActivityLogInvalidChannel),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c ActivityLogSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (ActivityLogSetupCommand) Execute(ctx registry.CommandContext, channelId *uint64) {
+	settings, err := redis.GetActivityLogSettings(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	// Event toggles are kept, in case logging is turned back on
+	if channelId == nil {
+		settings.ChannelId = 0
+
+		if err := redis.SetActivityLogSettings(ctx.GuildId(), settings); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupActivityLogDisabled)
+		return
+	}
+
+	ch, err := ctx.Worker().GetChannel(*channelId)
+	if err != nil {
+		if restError, ok := err.(request.RestError); ok && restError.IsClientError() {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupActivityLogInvalidChannel)
+		} else {
+			ctx.HandleError(err)
+		}
+
+		return
+	}
+
+	if ch.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupActivityLogInvalidChannel)
+		return
+	}
+
+	settings.ChannelId = ch.Id
+
+	if err := redis.SetActivityLogSettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupActivityLogSuccess, ch.Id)
+}
@@ -0,0 +1,97 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strings"
+)
+
+type ActivityLogEventSetupCommand struct{}
+
+// Selects every event type
+const activityLogEventAll = "all"
+
+func (c ActivityLogEventSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "activity-log-event",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredAutocompleteableArgument("event", "The type of ticket activity", interaction.OptionTypeString, i18n.SetupActivityLogInvalidEvent, c.EventAutoCompleteHandler),
+			command.NewRequiredArgument("enabled", "Whether the activity should be sent to the activity log channel", interaction.OptionTypeBoolean, "infallible"),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c ActivityLogEventSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (ActivityLogEventSetupCommand) Execute(ctx registry.CommandContext, rawEvent string, enabled bool) {
+	var events []redis.ActivityEvent
+	if strings.ToLower(rawEvent) == activityLogEventAll {
+		events = redis.ActivityEvents
+	} else {
+		event := redis.ActivityEvent(strings.ToLower(rawEvent))
+		if !utils.Contains(redis.ActivityEvents, event) {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupActivityLogInvalidEvent)
+			return
+		}
+
+		events = []redis.ActivityEvent{event}
+	}
+
+	settings, err := redis.GetActivityLogSettings(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	var disabled []redis.ActivityEvent
+	for _, event := range settings.DisabledEvents {
+		if !utils.Contains(events, event) {
+			disabled = append(disabled, event)
+		}
+	}
+
+	if !enabled {
+		disabled = append(disabled, events...)
+	}
+
+	settings.DisabledEvents = disabled
+
+	if err := redis.SetActivityLogSettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if enabled {
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupActivityLogEventEnabled, strings.ToLower(rawEvent))
+	} else {
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupActivityLogEventDisabled, strings.ToLower(rawEvent))
+	}
+}
+
+func (ActivityLogEventSetupCommand) EventAutoCompleteHandler(_ interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	var choices []interaction.ApplicationCommandOptionChoice
+	if strings.Contains(activityLogEventAll, strings.ToLower(value)) {
+		choices = append(choices, utils.StringChoice(activityLogEventAll))
+	}
+
+	for _, event := range redis.ActivityEvents {
+		if strings.Contains(string(event), strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(string(event)))
+		}
+	}
+
+	return choices
+}
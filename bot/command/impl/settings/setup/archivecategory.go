@@ -0,0 +1,85 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type ArchiveCategorySetupCommand struct{}
+
+func (ArchiveCategorySetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "archive-category",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("enabled", "Whether closed tickets should be moved to an archive category instead of being deleted", interaction.OptionTypeBoolean, "infallible"),
+			command.NewOptionalArgument("category", "The category that closed tickets should be moved to", interaction.OptionTypeChannel, i18n.SetupArchiveCategoryInvalid),
+			command.NewOptionalArgument("retention_days", "How many days closed tickets should be kept for before being deleted", interaction.OptionTypeInteger, i18n.SetupArchiveCategoryRetention),
+			command.NewOptionalArgument("overflow_category", "The category that closed tickets should be moved to once the archive category is full", interaction.OptionTypeChannel, i18n.SetupArchiveCategoryInvalid),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c ArchiveCategorySetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (ArchiveCategorySetupCommand) Execute(ctx registry.CommandContext, enabled bool, categoryId *uint64, retentionDays *int, overflowCategoryId *uint64) {
+	if !enabled {
+		if err := redis.DeleteArchiveCategorySettings(ctx.GuildId()); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupArchiveCategoryDisabled)
+		return
+	}
+
+	if categoryId == nil || !isCategory(ctx, *categoryId) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupArchiveCategoryInvalid)
+		return
+	}
+
+	if overflowCategoryId != nil && !isCategory(ctx, *overflowCategoryId) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupArchiveCategoryInvalid)
+		return
+	}
+
+	if retentionDays == nil || *retentionDays < 1 || *retentionDays > 365 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupArchiveCategoryRetention)
+		return
+	}
+
+	settings := redis.ArchiveCategorySettings{
+		Enabled:            true,
+		CategoryId:         *categoryId,
+		OverflowCategoryId: overflowCategoryId,
+		RetentionDays:      *retentionDays,
+	}
+
+	if err := redis.SetArchiveCategorySettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupArchiveCategorySuccess, *categoryId, *retentionDays)
+}
+
+func isCategory(ctx registry.CommandContext, channelId uint64) bool {
+	ch, err := ctx.Worker().GetChannel(channelId)
+	if err != nil {
+		return false
+	}
+
+	return ch.Type == channel.ChannelTypeGuildCategory
+}
@@ -0,0 +1,87 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strings"
+)
+
+type AutoAssignSetupCommand struct{}
+
+func (c AutoAssignSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "auto-assign",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("enabled", "Whether new tickets should be automatically assigned to a staff member", interaction.OptionTypeBoolean, "infallible"),
+			command.NewOptionalAutocompleteableArgument("strategy", "How the staff member should be chosen", interaction.OptionTypeString, i18n.SetupAutoAssignInvalidStrategy, c.AutoCompleteHandler),
+			command.NewOptionalArgument("workload_cap", "The maximum number of open tickets a staff member can have claimed before being skipped", interaction.OptionTypeInteger, i18n.SetupAutoAssignInvalidCap),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c AutoAssignSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (AutoAssignSetupCommand) Execute(ctx registry.CommandContext, enabled bool, strategy *string, workloadCap *int) {
+	if !enabled {
+		if err := redis.DeleteAutoAssignSettings(ctx.GuildId()); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupAutoAssignDisabled)
+		return
+	}
+
+	settings := redis.AutoAssignSettings{
+		Enabled:  true,
+		Strategy: redis.AutoAssignRoundRobin,
+	}
+
+	if strategy != nil {
+		settings.Strategy = redis.AutoAssignStrategy(*strategy)
+		if !utils.Contains(redis.AutoAssignStrategies, settings.Strategy) {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupAutoAssignInvalidStrategy)
+			return
+		}
+	}
+
+	if workloadCap != nil {
+		if *workloadCap < 0 || *workloadCap > 100 {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupAutoAssignInvalidCap)
+			return
+		}
+
+		settings.WorkloadCap = *workloadCap
+	}
+
+	if err := redis.SetAutoAssignSettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupAutoAssignSuccess, settings.Strategy)
+}
+
+func (AutoAssignSetupCommand) AutoCompleteHandler(_ interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, strategy := range redis.AutoAssignStrategies {
+		if strings.Contains(string(strategy), strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(string(strategy)))
+		}
+	}
+
+	return choices
+}
@@ -0,0 +1,134 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strings"
+	"time"
+)
+
+type BusinessHoursSetupCommand struct{}
+
+func (c BusinessHoursSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "business-hours",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("enabled", "Whether tickets should be treated differently outside of support hours", interaction.OptionTypeBoolean, "infallible"),
+			command.NewOptionalAutocompleteableArgument("panel", "The panel the schedule applies to. If not set, the schedule applies to all panels without their own", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, utils.AutoCompletePanels),
+			command.NewOptionalArgument("timezone", "The time zone the schedule is in, e.g. Europe/London", interaction.OptionTypeString, i18n.SetupBusinessHoursInvalidTimezone),
+			command.NewOptionalArgument("schedule", "When support is open, e.g. \"mon-fri 09:00-17:00, sat 22:00-06:00\"", interaction.OptionTypeString, i18n.SetupBusinessHoursInvalidSchedule),
+			command.NewOptionalArgument("holidays", "Comma separated dates that support is closed all day, e.g. \"2022-12-25, 2022-12-26\"", interaction.OptionTypeString, i18n.SetupBusinessHoursInvalidHolidays),
+			command.NewOptionalAutocompleteableArgument("mode", "Whether to refuse tickets outside of support hours, or open them with a notice", interaction.OptionTypeString, i18n.SetupBusinessHoursInvalidMode, c.ModeAutoCompleteHandler),
+			command.NewOptionalArgument("message", "The message to show when a ticket is refused", interaction.OptionTypeString, i18n.SetupBusinessHoursInvalidMessage),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c BusinessHoursSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (BusinessHoursSetupCommand) Execute(ctx registry.CommandContext, enabled bool, panelId *int, timezone, schedule, holidays, mode, message *string) {
+	if panelId != nil {
+		panel, err := dbclient.Client.Panel.GetById(*panelId)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if panel.PanelId == 0 || panel.GuildId != ctx.GuildId() {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageSwitchPanelInvalidPanel)
+			return
+		}
+	}
+
+	if !enabled {
+		if err := redis.DeleteBusinessHours(ctx.GuildId(), panelId); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupBusinessHoursDisabled)
+		return
+	}
+
+	if timezone == nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupBusinessHoursInvalidTimezone)
+		return
+	}
+
+	if _, err := time.LoadLocation(*timezone); err != nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupBusinessHoursInvalidTimezone)
+		return
+	}
+
+	if schedule == nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupBusinessHoursInvalidSchedule)
+		return
+	}
+
+	days, err := logic.ParseBusinessHoursSchedule(*schedule)
+	if err != nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupBusinessHoursInvalidSchedule)
+		return
+	}
+
+	hours := redis.BusinessHours{
+		Timezone: *timezone,
+		Days:     days,
+		Mode:     redis.BusinessHoursModeNotice,
+		Message:  message,
+	}
+
+	if holidays != nil {
+		hours.Holidays, err = logic.ParseHolidays(*holidays)
+		if err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupBusinessHoursInvalidHolidays)
+			return
+		}
+	}
+
+	if mode != nil {
+		hours.Mode = redis.BusinessHoursMode(*mode)
+		if !utils.Contains(redis.BusinessHoursModes, hours.Mode) {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupBusinessHoursInvalidMode)
+			return
+		}
+	}
+
+	if message != nil && len(*message) > 4096 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupBusinessHoursInvalidMessage)
+		return
+	}
+
+	if err := redis.SetBusinessHours(ctx.GuildId(), panelId, hours); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupBusinessHoursSuccess, hours.Timezone, hours.Mode)
+}
+
+func (BusinessHoursSetupCommand) ModeAutoCompleteHandler(_ interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, mode := range redis.BusinessHoursModes {
+		if strings.Contains(string(mode), strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(string(mode)))
+		}
+	}
+
+	return choices
+}
@@ -0,0 +1,181 @@
+package setup
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"regexp"
+	"strings"
+)
+
+type FormValidationSetupCommand struct{}
+
+func (c FormValidationSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "form-validation",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("enabled", "Whether answers to the form input should be validated", interaction.OptionTypeBoolean, "infallible"),
+			command.NewRequiredAutocompleteableArgument("input", "The form input to validate", interaction.OptionTypeInteger, i18n.SetupFormValidationInvalidInput, c.InputAutoCompleteHandler),
+			command.NewOptionalAutocompleteableArgument("format", "The format answers must be in", interaction.OptionTypeString, i18n.SetupFormValidationInvalidFormat, c.FormatAutoCompleteHandler),
+			command.NewOptionalArgument("min_length", "The minimum number of characters an answer must have", interaction.OptionTypeInteger, i18n.SetupFormValidationInvalidLength),
+			command.NewOptionalArgument("max_length", "The maximum number of characters an answer can have", interaction.OptionTypeInteger, i18n.SetupFormValidationInvalidLength),
+			command.NewOptionalArgument("pattern", "A regular expression that answers must match", interaction.OptionTypeString, i18n.SetupFormValidationInvalidPattern),
+			command.NewOptionalArgument("min", "The smallest number allowed, if the format is number", interaction.OptionTypeNumber, i18n.SetupFormValidationInvalidRange),
+			command.NewOptionalArgument("max", "The largest number allowed, if the format is number", interaction.OptionTypeNumber, i18n.SetupFormValidationInvalidRange),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c FormValidationSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (FormValidationSetupCommand) Execute(ctx registry.CommandContext, enabled bool, inputId int, format *string, minLength, maxLength *int, pattern *string, min, max *float64) {
+	input, ok, err := dbclient.Client.FormInput.Get(inputId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidInput)
+		return
+	}
+
+	form, ok, err := dbclient.Client.Forms.Get(input.FormId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok || form.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidInput)
+		return
+	}
+
+	if !enabled {
+		if err := redis.DeleteFormInputValidation(form.Id, input.Id); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupFormValidationDisabled, input.Label)
+		return
+	}
+
+	validation := redis.FormInputValidation{
+		MinLength: minLength,
+		MaxLength: maxLength,
+		Pattern:   pattern,
+		Format:    redis.FormInputFormatText,
+		Min:       min,
+		Max:       max,
+	}
+
+	if format != nil {
+		validation.Format = redis.FormInputFormat(*format)
+		if !utils.Contains(redis.FormInputFormats, validation.Format) {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidFormat)
+			return
+		}
+	}
+
+	inputMaxLength := logic.FormInputMaxLength(input)
+	if (minLength != nil && (*minLength < 0 || *minLength > inputMaxLength)) ||
+		(maxLength != nil && (*maxLength < 1 || *maxLength > inputMaxLength)) ||
+		(minLength != nil && maxLength != nil && *minLength > *maxLength) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidLength, inputMaxLength)
+		return
+	}
+
+	if pattern != nil {
+		if len(*pattern) > 200 {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidPattern)
+			return
+		}
+
+		if _, err := regexp.Compile(*pattern); err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidPattern)
+			return
+		}
+	}
+
+	if (min != nil || max != nil) && validation.Format != redis.FormInputFormatNumber {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidRange)
+		return
+	}
+
+	if min != nil && max != nil && *min > *max {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupFormValidationInvalidRange)
+		return
+	}
+
+	if err := redis.SetFormInputValidation(form.Id, input.Id, validation); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupFormValidationSuccess, input.Label, validation.Format)
+}
+
+func (FormValidationSetupCommand) InputAutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	if data.GuildId.Value == 0 {
+		return nil
+	}
+
+	forms, err := dbclient.Client.Forms.GetForms(data.GuildId.Value)
+	if err != nil {
+		sentry.Error(err) // TODO: Context
+		return nil
+	}
+
+	inputs, err := dbclient.Client.FormInput.GetInputsForGuild(data.GuildId.Value)
+	if err != nil {
+		sentry.Error(err) // TODO: Context
+		return nil
+	}
+
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, form := range forms {
+		for _, input := range inputs[form.Id] {
+			if len(choices) >= 25 {
+				return choices
+			}
+
+			name := fmt.Sprintf("%s: %s", form.Title, input.Label)
+			if strings.Contains(strings.ToLower(name), strings.ToLower(value)) {
+				choices = append(choices, interaction.ApplicationCommandOptionChoice{
+					Name:  utils.StringMax(name, 100),
+					Value: input.Id,
+				})
+			}
+		}
+	}
+
+	return choices
+}
+
+func (FormValidationSetupCommand) FormatAutoCompleteHandler(_ interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, format := range redis.FormInputFormats {
+		if strings.Contains(string(format), strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(string(format)))
+		}
+	}
+
+	return choices
+}
@@ -0,0 +1,56 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type HtmlTranscriptsSetupCommand struct{}
+
+func (HtmlTranscriptsSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "html-transcripts",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("archive-channel", "Whether an HTML transcript should be attached to the close log in the archive channel", interaction.OptionTypeBoolean, "infallible"),
+			command.NewRequiredArgument("direct-message", "Whether an HTML transcript should be attached to the close message sent to the ticket opener", interaction.OptionTypeBoolean, "infallible"),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c HtmlTranscriptsSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (HtmlTranscriptsSetupCommand) Execute(ctx registry.CommandContext, archiveChannel, directMessage bool) {
+	settings := redis.HtmlTranscriptSettings{
+		ArchiveChannel: archiveChannel,
+		DirectMessage:  directMessage,
+	}
+
+	if !settings.Enabled() {
+		if err := redis.DeleteHtmlTranscriptSettings(ctx.GuildId()); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupHtmlTranscriptsDisabled)
+		return
+	}
+
+	if err := redis.SetHtmlTranscriptSettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupHtmlTranscriptsSuccess)
+}
@@ -0,0 +1,67 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"github.com/rxdn/gdl/rest/request"
+)
+
+type MessageLogSetupCommand struct{}
+
+func (MessageLogSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "message-log",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewOptionalArgument("channel", "The channel that edits and deletions in tickets should be logged to. Leave blank to stop logging", interaction.OptionTypeChannel, i18n.SetupMessageLogInvalidChannel),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c MessageLogSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (MessageLogSetupCommand) Execute(ctx registry.CommandContext, channelId *uint64) {
+	if channelId == nil {
+		if err := redis.DeleteMessageLogChannel(ctx.GuildId()); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupMessageLogDisabled)
+		return
+	}
+
+	ch, err := ctx.Worker().GetChannel(*channelId)
+	if err != nil {
+		if restError, ok := err.(request.RestError); ok && restError.IsClientError() {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupMessageLogInvalidChannel)
+		} else {
+			ctx.HandleError(err)
+		}
+
+		return
+	}
+
+	if ch.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupMessageLogInvalidChannel)
+		return
+	}
+
+	if err := redis.SetMessageLogChannel(ctx.GuildId(), ch.Id); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupMessageLogSuccess, ch.Id)
+}
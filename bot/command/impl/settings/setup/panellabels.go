@@ -0,0 +1,75 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type PanelLabelsSetupCommand struct{}
+
+func (PanelLabelsSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "panel-labels",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredAutocompleteableArgument("panel", "The panel to set the default labels of", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, utils.AutoCompletePanels),
+			command.NewOptionalArgument("labels", "Comma separated labels to apply to tickets opened from the panel. Leave empty to clear", interaction.OptionTypeString, i18n.SetupPanelLabelsInvalid),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c PanelLabelsSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (PanelLabelsSetupCommand) Execute(ctx registry.CommandContext, panelId int, rawLabels *string) {
+	panel, err := dbclient.Client.Panel.GetById(panelId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if panel.PanelId == 0 || panel.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageSwitchPanelInvalidPanel)
+		return
+	}
+
+	var labels []string
+	if rawLabels != nil {
+		var ok bool
+		labels, ok = logic.ParseLabels(*rawLabels)
+		if !ok {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupPanelLabelsInvalid, redis.MaxLabelLength)
+			return
+		}
+	}
+
+	ok, err := redis.SetPanelLabels(ctx.GuildId(), panel.PanelId, labels)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !ok {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageLabelLimitReached, redis.MaxGuildLabels)
+		return
+	}
+
+	if len(labels) == 0 {
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupPanelLabelsCleared, panel.Title)
+	} else {
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupPanelLabelsSuccess, panel.Title, logic.FormatLabels(labels))
+	}
+}
@@ -0,0 +1,134 @@
+package setup
+
+import (
+	"errors"
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"regexp"
+	"strconv"
+)
+
+var roleIdPattern = regexp.MustCompile(`\d{17,20}`)
+
+type PanelRequirementsSetupCommand struct{}
+
+func (c PanelRequirementsSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "panel-requirements",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("enabled", "Whether users must meet requirements to open tickets from the panel", interaction.OptionTypeBoolean, "infallible"),
+			command.NewRequiredAutocompleteableArgument("panel", "The panel the requirements apply to", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, utils.AutoCompletePanels),
+			command.NewOptionalArgument("required_roles", "Roles that users must have at least one of, e.g. \"@Verified @Member\"", interaction.OptionTypeString, i18n.SetupPanelRequirementsInvalidRoles),
+			command.NewOptionalArgument("forbidden_roles", "Roles that prevent users from opening tickets, e.g. \"@Muted\"", interaction.OptionTypeString, i18n.SetupPanelRequirementsInvalidRoles),
+			command.NewOptionalArgument("min_account_age_days", "How many days old a user's account must be", interaction.OptionTypeInteger, i18n.SetupPanelRequirementsInvalidDays),
+			command.NewOptionalArgument("min_member_days", "How many days a user must have been in the server", interaction.OptionTypeInteger, i18n.SetupPanelRequirementsInvalidDays),
+			command.NewOptionalArgument("bloxlink", "Whether users must have linked a Roblox account with Bloxlink", interaction.OptionTypeBoolean, "infallible"),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c PanelRequirementsSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (PanelRequirementsSetupCommand) Execute(ctx registry.CommandContext, enabled bool, panelId int, requiredRoles, forbiddenRoles *string, minAccountAgeDays, minMemberDays *int, bloxlink *bool) {
+	panel, err := dbclient.Client.Panel.GetById(panelId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if panel.PanelId == 0 || panel.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageSwitchPanelInvalidPanel)
+		return
+	}
+
+	if !enabled {
+		if err := redis.DeletePanelRequirements(ctx.GuildId(), panelId); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupPanelRequirementsDisabled, panel.Title)
+		return
+	}
+
+	var requirements redis.PanelRequirements
+
+	if requiredRoles != nil {
+		requirements.RequiredRoles, err = parseRoleIds(*requiredRoles)
+		if err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupPanelRequirementsInvalidRoles)
+			return
+		}
+	}
+
+	if forbiddenRoles != nil {
+		requirements.ForbiddenRoles, err = parseRoleIds(*forbiddenRoles)
+		if err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupPanelRequirementsInvalidRoles)
+			return
+		}
+	}
+
+	if minAccountAgeDays != nil {
+		if *minAccountAgeDays < 0 || *minAccountAgeDays > 3650 {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupPanelRequirementsInvalidDays)
+			return
+		}
+
+		requirements.MinAccountAgeDays = *minAccountAgeDays
+	}
+
+	if minMemberDays != nil {
+		if *minMemberDays < 0 || *minMemberDays > 3650 {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupPanelRequirementsInvalidDays)
+			return
+		}
+
+		requirements.MinMemberDays = *minMemberDays
+	}
+
+	if bloxlink != nil {
+		requirements.RequireBloxlink = *bloxlink
+	}
+
+	if err := redis.SetPanelRequirements(ctx.GuildId(), panelId, requirements); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupPanelRequirementsSuccess, panel.Title)
+}
+
+// Returns an error if the string does not contain any role IDs
+func parseRoleIds(s string) ([]uint64, error) {
+	var roleIds []uint64
+	for _, match := range roleIdPattern.FindAllString(s, -1) {
+		roleId, err := strconv.ParseUint(match, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		roleIds = append(roleIds, roleId)
+	}
+
+	// Otherwise a typo would silently remove the requirement
+	if len(roleIds) == 0 {
+		return nil, errors.New("no role IDs found")
+	}
+
+	return roleIds, nil
+}
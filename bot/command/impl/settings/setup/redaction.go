@@ -0,0 +1,97 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strings"
+)
+
+type RedactionSetupCommand struct{}
+
+// Selects every built-in detector
+const redactionDetectorAll = "all"
+
+func (c RedactionSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "redaction",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredAutocompleteableArgument("detector", "The type of personal information to remove from transcripts", interaction.OptionTypeString, i18n.SetupRedactionInvalidDetector, c.DetectorAutoCompleteHandler),
+			command.NewRequiredArgument("enabled", "Whether the information should be removed from transcripts", interaction.OptionTypeBoolean, "infallible"),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c RedactionSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (RedactionSetupCommand) Execute(ctx registry.CommandContext, rawDetector string, enabled bool) {
+	var detectors []redis.RedactionDetector
+	if strings.ToLower(rawDetector) == redactionDetectorAll {
+		detectors = redis.RedactionDetectors
+	} else {
+		detector := redis.RedactionDetector(strings.ToLower(rawDetector))
+		if !utils.Contains(redis.RedactionDetectors, detector) {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupRedactionInvalidDetector)
+			return
+		}
+
+		detectors = []redis.RedactionDetector{detector}
+	}
+
+	settings, err := redis.GetRedactionSettings(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	var updated []redis.RedactionDetector
+	for _, detector := range settings.Detectors {
+		if !utils.Contains(detectors, detector) {
+			updated = append(updated, detector)
+		}
+	}
+
+	if enabled {
+		updated = append(updated, detectors...)
+	}
+
+	settings.Detectors = updated
+
+	if err := redis.SetRedactionSettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if enabled {
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupRedactionEnabled, strings.ToLower(rawDetector))
+	} else {
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupRedactionDisabled, strings.ToLower(rawDetector))
+	}
+}
+
+func (RedactionSetupCommand) DetectorAutoCompleteHandler(_ interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	var choices []interaction.ApplicationCommandOptionChoice
+	if strings.Contains(redactionDetectorAll, strings.ToLower(value)) {
+		choices = append(choices, utils.StringChoice(redactionDetectorAll))
+	}
+
+	for _, detector := range redis.RedactionDetectors {
+		if strings.Contains(string(detector), strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(string(detector)))
+		}
+	}
+
+	return choices
+}
@@ -0,0 +1,110 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"regexp"
+	"strings"
+)
+
+type RedactionRuleSetupCommand struct{}
+
+var redactionRuleNameRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+func (RedactionRuleSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "redaction-rule",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("name", "The name of the rule, shown in place of redacted values", interaction.OptionTypeString, i18n.SetupRedactionRuleInvalidName),
+			command.NewOptionalArgument("pattern", "A regular expression matching the values to remove from transcripts. Leave blank to remove the rule", interaction.OptionTypeString, i18n.SetupRedactionRuleInvalidPattern),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c RedactionRuleSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (RedactionRuleSetupCommand) Execute(ctx registry.CommandContext, name string, pattern *string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !redactionRuleNameRegex.MatchString(name) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupRedactionRuleInvalidName)
+		return
+	}
+
+	settings, err := redis.GetRedactionSettings(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	index := -1
+	for i, rule := range settings.CustomRules {
+		if rule.Name == name {
+			index = i
+			break
+		}
+	}
+
+	if pattern == nil {
+		if index == -1 {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupRedactionRuleNotFound, name)
+			return
+		}
+
+		settings.CustomRules = append(settings.CustomRules[:index], settings.CustomRules[index+1:]...)
+
+		if err := redis.SetRedactionSettings(ctx.GuildId(), settings); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupRedactionRuleRemoved, name)
+		return
+	}
+
+	if len(*pattern) > 200 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupRedactionRuleInvalidPattern)
+		return
+	}
+
+	// Patterns that match the empty string would redact between every character
+	regex, err := regexp.Compile(*pattern)
+	if err != nil || regex.MatchString("") {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupRedactionRuleInvalidPattern)
+		return
+	}
+
+	rule := redis.RedactionRule{
+		Name:    name,
+		Pattern: *pattern,
+	}
+
+	if index == -1 {
+		if len(settings.CustomRules) >= redis.MaxRedactionRules {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupRedactionRuleLimit, redis.MaxRedactionRules)
+			return
+		}
+
+		settings.CustomRules = append(settings.CustomRules, rule)
+	} else {
+		settings.CustomRules[index] = rule
+	}
+
+	if err := redis.SetRedactionSettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupRedactionRuleAdded, name)
+}
@@ -31,6 +31,21 @@ func (SetupCommand) Properties() registry.Properties {
 			TranscriptsSetupCommand{},
 			CategorySetupCommand{},
 			ThreadsSetupCommand{},
+			ArchiveCategorySetupCommand{},
+			AutoAssignSetupCommand{},
+			BusinessHoursSetupCommand{},
+			PanelRequirementsSetupCommand{},
+			TicketLimitsSetupCommand{},
+			FormValidationSetupCommand{},
+			PanelLabelsSetupCommand{},
+			StaffNotesSetupCommand{},
+			HtmlTranscriptsSetupCommand{},
+			RedactionSetupCommand{},
+			RedactionRuleSetupCommand{},
+			ActivityLogSetupCommand{},
+			ActivityLogEventSetupCommand{},
+			MessageLogSetupCommand{},
+			TranscriptViewerSetupCommand{},
 		},
 	}
 }
@@ -0,0 +1,87 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strings"
+)
+
+type StaffNotesSetupCommand struct{}
+
+func (c StaffNotesSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "staff-notes",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("enabled", "Whether a private place for staff to leave notes should be created for each ticket", interaction.OptionTypeBoolean, "infallible"),
+			command.NewOptionalAutocompleteableArgument("mode", "Whether notes should be kept in a private thread in the ticket, or a separate channel", interaction.OptionTypeString, i18n.SetupStaffNotesInvalidMode, c.ModeAutoCompleteHandler),
+			command.NewOptionalArgument("category", "The category that notes channels should be created in. Defaults to the ticket's category", interaction.OptionTypeChannel, i18n.SetupStaffNotesInvalidCategory),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c StaffNotesSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (StaffNotesSetupCommand) Execute(ctx registry.CommandContext, enabled bool, mode *string, categoryId *uint64) {
+	if !enabled {
+		if err := redis.DeleteStaffNotesSettings(ctx.GuildId()); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupStaffNotesDisabled)
+		return
+	}
+
+	settings := redis.StaffNotesSettings{
+		Enabled: true,
+		Mode:    redis.StaffNotesModeThread,
+	}
+
+	if mode != nil {
+		settings.Mode = redis.StaffNotesMode(strings.ToLower(*mode))
+		if !utils.Contains(redis.StaffNotesModes, settings.Mode) {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupStaffNotesInvalidMode)
+			return
+		}
+	}
+
+	if categoryId != nil {
+		if settings.Mode != redis.StaffNotesModeChannel || !isCategory(ctx, *categoryId) {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.SetupStaffNotesInvalidCategory)
+			return
+		}
+
+		settings.CategoryId = categoryId
+	}
+
+	if err := redis.SetStaffNotesSettings(ctx.GuildId(), settings); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupStaffNotesSuccess, settings.Mode)
+}
+
+func (StaffNotesSetupCommand) ModeAutoCompleteHandler(_ interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, mode := range redis.StaffNotesModes {
+		if strings.Contains(string(mode), strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(string(mode)))
+		}
+	}
+
+	return choices
+}
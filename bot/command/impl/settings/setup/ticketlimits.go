@@ -0,0 +1,115 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type TicketLimitsSetupCommand struct{}
+
+func (c TicketLimitsSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "ticket-limits",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewOptionalArgument("limit", "The maximum amount of tickets a user can have open simultaneously. 0 removes the limit", interaction.OptionTypeInteger, i18n.SetupTicketLimitsInvalid),
+			command.NewOptionalAutocompleteableArgument("panel", "The panel the limit applies to", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, utils.AutoCompletePanels),
+			command.NewOptionalArgument("role", "The role the limit applies to, replacing the server-wide limit", interaction.OptionTypeRole, i18n.SetupTicketLimitsInvalidTarget),
+			command.NewOptionalArgument("redirect_duplicates", "Whether to link users to their existing ticket instead of opening another from the same panel", interaction.OptionTypeBoolean, "infallible"),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c TicketLimitsSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (TicketLimitsSetupCommand) Execute(ctx registry.CommandContext, limit, panelId *int, roleId *uint64, redirectDuplicates *bool) {
+	if limit == nil && redirectDuplicates == nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupTicketLimitsInvalid)
+		return
+	}
+
+	// Limits are set for either a panel or a role, the guild-wide limit is set with /setup limit
+	if limit != nil && (panelId == nil) == (roleId == nil) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupTicketLimitsInvalidTarget)
+		return
+	}
+
+	if limit != nil && (*limit < 0 || *limit > 10) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupTicketLimitsInvalid)
+		return
+	}
+
+	limits, err := redis.GetTicketLimits(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	var messageId i18n.MessageId
+	var format []interface{}
+
+	if limit != nil && panelId != nil {
+		panel, err := dbclient.Client.Panel.GetById(*panelId)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if panel.PanelId == 0 || panel.GuildId != ctx.GuildId() {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageSwitchPanelInvalidPanel)
+			return
+		}
+
+		if *limit == 0 {
+			delete(limits.PanelLimits, panel.PanelId)
+			messageId, format = i18n.SetupTicketLimitsPanelRemoved, []interface{}{panel.Title}
+		} else {
+			if limits.PanelLimits == nil {
+				limits.PanelLimits = make(map[int]int)
+			}
+
+			limits.PanelLimits[panel.PanelId] = *limit
+			messageId, format = i18n.SetupTicketLimitsPanel, []interface{}{*limit, panel.Title}
+		}
+	} else if limit != nil && roleId != nil {
+		if *limit == 0 {
+			delete(limits.RoleLimits, *roleId)
+			messageId, format = i18n.SetupTicketLimitsRoleRemoved, []interface{}{*roleId}
+		} else {
+			if limits.RoleLimits == nil {
+				limits.RoleLimits = make(map[uint64]int)
+			}
+
+			limits.RoleLimits[*roleId] = *limit
+			messageId, format = i18n.SetupTicketLimitsRole, []interface{}{*limit, *roleId}
+		}
+	}
+
+	if redirectDuplicates != nil {
+		limits.RedirectDuplicates = *redirectDuplicates
+
+		if messageId == "" {
+			messageId, format = i18n.SetupTicketLimitsDuplicates, []interface{}{*redirectDuplicates}
+		}
+	}
+
+	if err := redis.SetTicketLimits(ctx.GuildId(), limits); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, messageId, format...)
+}
@@ -0,0 +1,89 @@
+package setup
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"net/url"
+)
+
+// Short keys could be brute forced from a signed link
+const minTranscriptSigningKeyLength = 32
+
+type TranscriptViewerSetupCommand struct{}
+
+func (TranscriptViewerSetupCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "transcript-viewer",
+		Description:     i18n.HelpSetup,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Settings,
+		Arguments: command.Arguments(
+			command.NewOptionalArgument("url", "Whitelabel only: the base URL of your transcript viewer. Leave both options blank to use the default", interaction.OptionTypeString, i18n.SetupTranscriptViewerInvalid),
+			command.NewOptionalArgument("signing_key", "The key your viewer checks transcript links with", interaction.OptionTypeString, i18n.SetupTranscriptViewerInvalid),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c TranscriptViewerSetupCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (TranscriptViewerSetupCommand) Execute(ctx registry.CommandContext, viewerUrl, signingKey *string) {
+	if !ctx.Worker().IsWhitelabel {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupTranscriptViewerNotWhitelabel)
+		return
+	}
+
+	// The viewer applies to every server the bot is in, so only the bot's owner can change it
+	bot, err := dbclient.Client.Whitelabel.GetByBotId(ctx.Worker().BotId)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if bot.UserId != ctx.UserId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageOwnerOnly)
+		return
+	}
+
+	if viewerUrl == nil && signingKey == nil {
+		if err := redis.DeleteTranscriptViewer(ctx.Worker().BotId); err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupTranscriptViewerRemoved)
+		return
+	}
+
+	if viewerUrl == nil || signingKey == nil || len(*signingKey) < minTranscriptSigningKeyLength {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupTranscriptViewerInvalid, minTranscriptSigningKeyLength)
+		return
+	}
+
+	parsed, err := url.Parse(*viewerUrl)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.SetupTranscriptViewerInvalid, minTranscriptSigningKeyLength)
+		return
+	}
+
+	viewer := redis.TranscriptViewer{
+		Url:        parsed.String(),
+		SigningKey: *signingKey,
+	}
+
+	if err := redis.SetTranscriptViewer(ctx.Worker().BotId, viewer); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleSetup, i18n.SetupTranscriptViewerSuccess, viewer.Url)
+}
@@ -9,12 +9,15 @@ import (
 	"github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/channel/embed"
 	"github.com/rxdn/gdl/objects/interaction"
 	"golang.org/x/sync/errgroup"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -82,6 +85,13 @@ func (StatsServerCommand) Execute(ctx registry.CommandContext) {
 		return
 	})
 
+	// label breakdown
+	var labelStats []labelStatistics
+	group.Go(func() (err error) {
+		labelStats, err = getLabelStatistics(ctx.GuildId())
+		return
+	})
+
 	if err := group.Wait(); err != nil {
 		ctx.HandleError(err)
 		return
@@ -103,6 +113,15 @@ func (StatsServerCommand) Execute(ctx registry.CommandContext) {
 		AddField("Average Ticket Duration (Monthly)", formatNullableTime(ticketDuration.Monthly), true).
 		AddField("Average Ticket Duration (Weekly)", formatNullableTime(ticketDuration.Weekly), true)
 
+	if len(labelStats) > 0 {
+		lines := make([]string, len(labelStats))
+		for i, stats := range labelStats {
+			lines[i] = fmt.Sprintf("`%s` • %d tickets • %s", stats.Label, stats.Tickets, formatNullableTime(stats.ResponseTime))
+		}
+
+		msgEmbed.AddField("Tickets By Label (Average First Response Time)", utils.StringMax(strings.Join(lines, "\n"), 1024), false)
+	}
+
 	_, _ = ctx.ReplyWith(command.NewEphemeralEmbedMessageResponse(msgEmbed))
 	ctx.Accept()
 }
@@ -110,3 +129,46 @@ func (StatsServerCommand) Execute(ctx registry.CommandContext) {
 func formatNullableTime(duration *time.Duration) string {
 	return utils.FormatNullableTime(duration)
 }
+
+type labelStatistics struct {
+	Label        string
+	Tickets      int
+	ResponseTime *time.Duration
+}
+
+// Returns the statistics for each of the guild's labels, most used first
+func getLabelStatistics(guildId uint64) ([]labelStatistics, error) {
+	labels, err := redis.GetGuildLabels(guildId)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]labelStatistics, len(labels))
+	for i, label := range labels {
+		ticketIds, err := redis.GetLabelTicketIds(guildId, label)
+		if err != nil {
+			return nil, err
+		}
+
+		responseTime, err := dbclient.GetAverageFirstResponseTime(guildId, ticketIds)
+		if err != nil {
+			return nil, err
+		}
+
+		stats[i] = labelStatistics{
+			Label:        label,
+			Tickets:      len(ticketIds),
+			ResponseTime: responseTime,
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Tickets == stats[j].Tickets {
+			return stats[i].Label < stats[j].Label
+		}
+
+		return stats[i].Tickets > stats[j].Tickets
+	})
+
+	return stats, nil
+}
@@ -59,42 +59,21 @@ func (AddCommand) Execute(ctx registry.CommandContext, userId uint64) {
 		return
 	}
 
-	// Add user to ticket in DB
-	if err := dbclient.Client.TicketMembers.Add(ctx.GuildId(), ticket.Id, userId); err != nil {
-		ctx.HandleError(err)
-		return
-	}
-
-	if ticket.IsThread {
-		if err := ctx.Worker().AddThreadMember(*ticket.ChannelId, userId); err != nil {
-			if err, ok := err.(request.RestError); ok && err.ApiError.Message == "Missing Access" {
-				ch, err := ctx.Worker().GetChannel(ctx.ChannelId())
-				if err != nil {
-					ctx.HandleError(err)
-					return
-				}
-
-				ctx.Reply(customisation.Red, i18n.Error, i18n.MessageOpenCantSeeParentChannel, userId, ch.ParentId.Value)
-			} else {
+	// Add user to ticket
+	if err := logic.AddTicketMember(ctx, ticket, userId); err != nil {
+		if err, ok := err.(request.RestError); ok && ticket.IsThread && err.ApiError.Message == "Missing Access" {
+			ch, err := ctx.Worker().GetChannel(ctx.ChannelId())
+			if err != nil {
 				ctx.HandleError(err)
+				return
 			}
 
-			return
-		}
-	} else {
-		// Build permissions
-		additionalPermissions, err := dbclient.Client.TicketPermissions.Get(ctx.GuildId())
-		if err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageOpenCantSeeParentChannel, userId, ch.ParentId.Value)
+		} else {
 			ctx.HandleError(err)
-			return
 		}
 
-		// ticket.ChannelId cannot be nil, as we get by channel id
-		data := logic.BuildUserOverwrite(userId, additionalPermissions)
-		if err := ctx.Worker().EditChannelPermissions(*ticket.ChannelId, data); err != nil {
-			ctx.HandleError(err)
-			return
-		}
+		return
 	}
 
 	ctx.ReplyPermanent(customisation.Green, i18n.TitleAdd, i18n.MessageAddSuccess, userId, *ticket.ChannelId)
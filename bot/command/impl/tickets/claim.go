@@ -9,7 +9,6 @@ import (
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/logic"
 	"github.com/TicketsBot/worker/i18n"
-	"github.com/rxdn/gdl/objects/channel"
 	"github.com/rxdn/gdl/objects/interaction"
 )
 
@@ -45,18 +44,6 @@ func (ClaimCommand) Execute(ctx registry.CommandContext) {
 		return
 	}
 
-	// Check if thread
-	ch, err := ctx.Worker().GetChannel(ctx.ChannelId())
-	if err != nil {
-		ctx.HandleError(err)
-		return
-	}
-
-	if ch.Type == channel.ChannelTypeGuildPrivateThread {
-		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageClaimThread)
-		return
-	}
-
 	if err := logic.ClaimTicket(ctx, ticket, ctx.UserId()); err != nil {
 		ctx.HandleError(err)
 		return
@@ -0,0 +1,118 @@
+package tickets
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/gofrs/uuid"
+	"github.com/rxdn/gdl/objects/interaction"
+	"github.com/rxdn/gdl/objects/interaction/component"
+	"time"
+)
+
+type CloseAllCommand struct {
+}
+
+func (c CloseAllCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "closeall",
+		Description:     i18n.HelpCloseAll,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewOptionalAutocompleteableArgument("panel", "Only close tickets opened from this panel", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, c.AutoCompleteHandler),
+			command.NewOptionalArgument("inactive_hours", "Only close tickets that have had no messages for this many hours", interaction.OptionTypeInteger, i18n.MessageCloseAllInvalidHours),
+			command.NewOptionalArgument("unclaimed", "Only close tickets that have not been claimed", interaction.OptionTypeBoolean, "infallible"),
+			command.NewOptionalArgument("claimed_by", "Only close tickets claimed by this user", interaction.OptionTypeUser, i18n.MessageInvalidUser),
+			command.NewOptionalArgument("opened_before", "Only close tickets opened before this date (YYYY-MM-DD)", interaction.OptionTypeString, i18n.MessageCloseAllInvalidDate),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c CloseAllCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (CloseAllCommand) Execute(ctx registry.CommandContext, panelId *int, inactiveHours *int, unclaimed *bool, claimedBy *uint64, openedBefore *string) {
+	filter := redis.BulkCloseFilter{
+		PanelId:       panelId,
+		InactiveHours: inactiveHours,
+		ClaimedBy:     claimedBy,
+	}
+
+	if panelId != nil && !isGuildPanel(ctx, *panelId) {
+		return
+	}
+
+	if inactiveHours != nil && *inactiveHours < 1 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageCloseAllInvalidHours)
+		return
+	}
+
+	if unclaimed != nil {
+		filter.Unclaimed = *unclaimed
+	}
+
+	if filter.Unclaimed && claimedBy != nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageCloseAllConflictingClaim)
+		return
+	}
+
+	if openedBefore != nil {
+		date, err := time.Parse("2006-01-02", *openedBefore)
+		if err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageCloseAllInvalidDate)
+			return
+		}
+
+		filter.OpenedBefore = &date
+	}
+
+	// Dry run, to show how many tickets would be closed
+	tickets, err := logic.FindBulkCloseTickets(ctx.GuildId(), filter)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if len(tickets) == 0 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageCloseAllNoTickets)
+		return
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if err := redis.StoreBulkCloseFilter(ctx.GuildId(), id.String(), filter); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	e := utils.BuildEmbed(ctx, customisation.Orange, i18n.TitleCloseAll, i18n.MessageCloseAllConfirm, nil, len(tickets))
+	res := command.NewEphemeralEmbedMessageResponseWithComponents(e, utils.Slice(component.BuildActionRow(
+		component.BuildButton(component.Button{
+			Label:    ctx.GetMessage(i18n.Confirm),
+			CustomId: fmt.Sprintf("closeall_confirm_%s", id.String()),
+			Style:    component.ButtonStyleDanger,
+		}),
+	)))
+
+	if _, err := ctx.ReplyWith(res); err != nil {
+		ctx.HandleError(err)
+	}
+}
+
+func (CloseAllCommand) AutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	return utils.AutoCompletePanels(data, value)
+}
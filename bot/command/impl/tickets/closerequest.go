@@ -8,6 +8,7 @@ import (
 	"github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/channel/embed"
@@ -71,7 +72,7 @@ func (CloseRequestCommand) Execute(ctx registry.CommandContext, closeDelay *int,
 		Reason:   reason,
 	}
 
-	if err := dbclient.Client.CloseRequest.Set(closeRequest); err != nil {
+	if err := logic.RequestClose(ctx, ticket, closeRequest); err != nil {
 		ctx.HandleError(err)
 		return
 	}
@@ -0,0 +1,92 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/interaction"
+	"sort"
+	"strings"
+)
+
+type LabelCommand struct {
+}
+
+func (LabelCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "label",
+		Description:     i18n.HelpLabel,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Support,
+		Children: []registry.Command{
+			LabelAddCommand{},
+			LabelRemoveCommand{},
+			LabelDeleteCommand{},
+		},
+		Category: command.Tickets,
+	}
+}
+
+func (c LabelCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (LabelCommand) Execute(ctx registry.CommandContext) {
+	usageEmbed := embed.EmbedField{
+		Name:   "Usage",
+		Value:  "`/label add`\n`/label remove`\n`/label delete`",
+		Inline: false,
+	}
+
+	ctx.ReplyWithFields(customisation.Red, i18n.Error, i18n.MessageInvalidArgument, utils.ToSlice(usageEmbed))
+	ctx.Reject()
+}
+
+func autoCompleteLabels(labels []string, value string) []interaction.ApplicationCommandOptionChoice {
+	sort.Strings(labels)
+
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, label := range labels {
+		if len(choices) >= 25 {
+			break
+		}
+
+		if strings.Contains(label, strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(label))
+		}
+	}
+
+	return choices
+}
+
+func autoCompleteGuildLabels(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	if data.GuildId.Value == 0 {
+		return nil
+	}
+
+	labels, err := redis.GetGuildLabels(data.GuildId.Value)
+	if err != nil {
+		sentry.Error(err) // TODO: Context
+		return nil
+	}
+
+	return autoCompleteLabels(labels, value)
+}
+
+// Validates the label, replying to the user if it is invalid
+func normaliseLabel(ctx registry.CommandContext, label string) (string, bool) {
+	label, ok := logic.NormaliseLabel(label)
+	if !ok {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageLabelInvalid, redis.MaxLabelLength)
+		return "", false
+	}
+
+	return label, true
+}
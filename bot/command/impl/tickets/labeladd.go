@@ -0,0 +1,87 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type LabelAddCommand struct {
+}
+
+func (c LabelAddCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "add",
+		Description:     i18n.HelpLabelAdd,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Support,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewRequiredAutocompleteableArgument("label", "The label to apply to the ticket. New labels are created automatically", interaction.OptionTypeString, i18n.MessageLabelInvalid, c.AutoCompleteHandler),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c LabelAddCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (LabelAddCommand) Execute(ctx registry.CommandContext, label string) {
+	ticket, err := dbclient.Client.Tickets.GetByChannelAndGuild(ctx.ChannelId(), ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if ticket.Id == 0 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageNotATicketChannel)
+		return
+	}
+
+	label, ok := normaliseLabel(ctx, label)
+	if !ok {
+		return
+	}
+
+	added, err := logic.AddTicketLabel(ctx, ticket, label)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !added {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageLabelLimitReached, redis.MaxGuildLabels)
+		return
+	}
+
+	ctx.ReplyPermanent(customisation.Green, i18n.TitleLabel, i18n.MessageLabelAdded, label)
+}
+
+// AutoCompleteHandler suggests existing labels, as well as what the user has typed, so that new labels can be created
+func (LabelAddCommand) AutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	choices := autoCompleteGuildLabels(data, value)
+
+	if label, ok := logic.NormaliseLabel(value); ok {
+		for _, choice := range choices {
+			if choice.Value == label {
+				return choices
+			}
+		}
+
+		if len(choices) >= 25 {
+			choices = choices[:24]
+		}
+
+		choices = append([]interaction.ApplicationCommandOptionChoice{utils.StringChoice(label)}, choices...)
+	}
+
+	return choices
+}
@@ -0,0 +1,69 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type LabelDeleteCommand struct {
+}
+
+func (c LabelDeleteCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "delete",
+		Description:     i18n.HelpLabelDelete,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Admin,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewRequiredAutocompleteableArgument("label", "The label to delete from the server, and every ticket", interaction.OptionTypeString, i18n.MessageLabelInvalid, autoCompleteGuildLabels),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c LabelDeleteCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (LabelDeleteCommand) Execute(ctx registry.CommandContext, label string) {
+	label, ok := normaliseLabel(ctx, label)
+	if !ok {
+		return
+	}
+
+	exists, err := redis.IsGuildLabel(ctx.GuildId(), label)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !exists {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageLabelNotFound, label)
+		return
+	}
+
+	panels, err := dbclient.Client.Panel.GetByGuild(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	panelIds := make([]int, len(panels))
+	for i, panel := range panels {
+		panelIds[i] = panel.PanelId
+	}
+
+	if err := redis.DeleteGuildLabel(ctx.GuildId(), label, panelIds); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleLabel, i18n.MessageLabelDeleted, label)
+}
@@ -0,0 +1,91 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type LabelRemoveCommand struct {
+}
+
+func (c LabelRemoveCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "remove",
+		Description:     i18n.HelpLabelRemove,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Support,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewRequiredAutocompleteableArgument("label", "The label to remove from the ticket", interaction.OptionTypeString, i18n.MessageLabelInvalid, c.AutoCompleteHandler),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c LabelRemoveCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (LabelRemoveCommand) Execute(ctx registry.CommandContext, label string) {
+	ticket, err := dbclient.Client.Tickets.GetByChannelAndGuild(ctx.ChannelId(), ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if ticket.Id == 0 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageNotATicketChannel)
+		return
+	}
+
+	label, ok := normaliseLabel(ctx, label)
+	if !ok {
+		return
+	}
+
+	removed, err := logic.RemoveTicketLabel(ctx, ticket, label)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !removed {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageLabelNotApplied, label)
+		return
+	}
+
+	ctx.ReplyPermanent(customisation.Green, i18n.TitleLabel, i18n.MessageLabelRemoved, label)
+}
+
+// AutoCompleteHandler suggests the labels applied to the ticket the command is being run in
+func (LabelRemoveCommand) AutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	if data.GuildId.Value == 0 {
+		return nil
+	}
+
+	ticket, err := dbclient.Client.Tickets.GetByChannelAndGuild(data.ChannelId, data.GuildId.Value)
+	if err != nil {
+		sentry.Error(err) // TODO: Context
+		return nil
+	}
+
+	if ticket.Id == 0 {
+		return nil
+	}
+
+	labels, err := redis.GetTicketLabels(ticket.GuildId, ticket.Id)
+	if err != nil {
+		sentry.Error(err) // TODO: Context
+		return nil
+	}
+
+	return autoCompleteLabels(labels, value)
+}
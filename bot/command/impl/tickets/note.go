@@ -0,0 +1,66 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type NoteCommand struct {
+}
+
+func (NoteCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "note",
+		Description:     i18n.HelpNote,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Support,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewRequiredArgument("text", "The note to leave for other staff members", interaction.OptionTypeString, i18n.MessageInvalidArgument),
+		),
+		// The note would be visible to the ticket opener if sent as a message
+		InteractionOnly: true,
+	}
+}
+
+func (c NoteCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (NoteCommand) Execute(ctx registry.CommandContext, text string) {
+	ticket, err := dbclient.Client.Tickets.GetByChannelAndGuild(ctx.ChannelId(), ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if ticket.Id == 0 {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageNotATicketChannel)
+		return
+	}
+
+	if ticket.IsThread {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageStaffNotesThreadMode)
+		return
+	}
+
+	channelId, err := logic.PostStaffNote(ctx, ticket, utils.StringMax(text, 4096))
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if channelId == nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageStaffNotesDisabled)
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleStaffNotes, i18n.MessageNoteAdded, *channelId)
+}
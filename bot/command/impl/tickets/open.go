@@ -2,11 +2,13 @@ package tickets
 
 import (
 	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/database"
 	"github.com/TicketsBot/worker/bot/command"
 	"github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/interaction"
 )
@@ -24,6 +26,8 @@ func (OpenCommand) Properties() registry.Properties {
 		Category:        command.Tickets,
 		Arguments: command.Arguments(
 			command.NewOptionalArgument("subject", "The subject of the ticket", interaction.OptionTypeString, "infallible"),
+			command.NewOptionalArgumentInteractionOnly("user", "Staff only: the user to open the ticket on behalf of", interaction.OptionTypeUser, i18n.MessageInvalidUser),
+			command.NewOptionalAutocompleteableArgumentInteractionOnly("panel", "Staff only: the panel to open the ticket from", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, utils.AutoCompletePanels),
 		),
 		DefaultEphemeral: true,
 	}
@@ -33,7 +37,17 @@ func (c OpenCommand) GetExecutor() interface{} {
 	return c.Execute
 }
 
-func (OpenCommand) Execute(ctx registry.CommandContext, providedSubject *string) {
+func (OpenCommand) Execute(ctx registry.CommandContext, providedSubject *string, userId *uint64, panelId *int) {
+	var subject string
+	if providedSubject != nil {
+		subject = *providedSubject
+	}
+
+	if userId != nil || panelId != nil {
+		openOnBehalf(ctx, subject, userId, panelId)
+		return
+	}
+
 	settings, err := dbclient.Client.Settings.Get(ctx.GuildId())
 	if err != nil {
 		ctx.HandleError(err)
@@ -45,10 +59,54 @@ func (OpenCommand) Execute(ctx registry.CommandContext, providedSubject *string)
 		return
 	}
 
-	var subject string
-	if providedSubject != nil {
-		subject = *providedSubject
+	logic.OpenTicket(ctx, nil, subject, nil)
+}
+
+// Staff can open tickets for other users, e.g. for moderation. As this is not the user opening a ticket themselves,
+// it is allowed even if the open command is disabled.
+func openOnBehalf(ctx registry.CommandContext, subject string, userId *uint64, panelId *int) {
+	permissionLevel, err := ctx.UserPermissionLevel()
+	if err != nil {
+		ctx.HandleError(err)
+		return
 	}
 
-	logic.OpenTicket(ctx, nil, subject, nil)
+	if permissionLevel < permission.Support {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageNoPermission)
+		return
+	}
+
+	var panel *database.Panel
+	if panelId != nil {
+		p, err := dbclient.Client.Panel.GetById(*panelId)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if p.PanelId == 0 || p.GuildId != ctx.GuildId() {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageSwitchPanelInvalidPanel)
+			return
+		}
+
+		panel = &p
+	}
+
+	if userId == nil || *userId == ctx.UserId() {
+		logic.OpenTicket(ctx, panel, subject, nil)
+		return
+	}
+
+	member, err := ctx.Worker().GetGuildMember(ctx.GuildId(), *userId)
+	if err != nil {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageOpenOnBehalfInvalidUser)
+		return
+	}
+
+	if member.User.Bot {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageOpenOnBehalfInvalidUser)
+		return
+	}
+
+	logic.OpenTicketOnBehalf(ctx, panel, subject, *userId)
 }
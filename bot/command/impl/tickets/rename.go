@@ -6,11 +6,11 @@ import (
 	"github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/channel/embed"
 	"github.com/rxdn/gdl/objects/interaction"
-	"github.com/rxdn/gdl/rest"
 )
 
 type RenameCommand struct {
@@ -57,11 +57,7 @@ func (RenameCommand) Execute(ctx registry.CommandContext, name string) {
 		return
 	}
 
-	data := rest.ModifyChannelData{
-		Name: name,
-	}
-
-	if _, err := ctx.Worker().ModifyChannel(ctx.ChannelId(), data); err != nil {
+	if err := logic.RenameTicket(ctx, ticket, name); err != nil {
 		ctx.HandleError(err)
 		return
 	}
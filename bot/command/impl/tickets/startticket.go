@@ -94,10 +94,6 @@ func (StartTicketCommand) Execute(ctx registry.CommandContext) {
 			}
 			
 			sendMovedMessage(ctx, ticket, msg)
-			if err := dbclient.Client.TicketMembers.Add(ticket.GuildId, ticket.Id, msg.Author.Id); err != nil {
-				ctx.HandleError(err)
-				return
-			}
 		}
 	}
 }
@@ -126,21 +122,7 @@ func addMessageSender(ctx registry.CommandContext, ticket database.Ticket, msg m
 		return nil
 	}
 
-	if ticket.IsThread {
-		if err := ctx.Worker().AddThreadMember(*ticket.ChannelId, msg.Author.Id); err != nil {
-			if err, ok := err.(request.RestError); ok && err.ApiError.Message == "Missing Access" {
-				ch, err := ctx.Worker().GetChannel(ctx.ChannelId())
-				if err != nil {
-					return err
-				}
-
-				ctx.Reply(customisation.Red, i18n.Error, i18n.MessageOpenCantSeeParentChannel, msg.Author.Id, ch.ParentId.Value)
-				return nil
-			} else {
-				return err
-			}
-		}
-	} else {
+	if !ticket.IsThread {
 		// Get perms
 		ch, err := ctx.Worker().GetChannel(*ticket.ChannelId)
 		if err != nil {
@@ -153,17 +135,20 @@ func addMessageSender(ctx registry.CommandContext, ticket database.Ticket, msg m
 				return nil
 			}
 		}
+	}
 
-		// Build permissions
-		additionalPermissions, err := dbclient.Client.TicketPermissions.Get(ctx.GuildId())
-		if err != nil {
-			return err
-		}
+	if err := logic.AddTicketMember(ctx, ticket, msg.Author.Id); err != nil {
+		if err, ok := err.(request.RestError); ok && ticket.IsThread && err.ApiError.Message == "Missing Access" {
+			ch, err := ctx.Worker().GetChannel(ctx.ChannelId())
+			if err != nil {
+				return err
+			}
 
-		overwrite := logic.BuildUserOverwrite(msg.Author.Id, additionalPermissions)
-		if err := ctx.Worker().EditChannelPermissions(*ticket.ChannelId, overwrite); err != nil {
-			return err
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageOpenCantSeeParentChannel, msg.Author.Id, ch.ParentId.Value)
+			return nil
 		}
+
+		return err
 	}
 
 	return nil
@@ -66,7 +66,7 @@ func (SwitchPanelCommand) Execute(ctx registry.CommandContext, panelId int) {
 	}
 
 	// Update panel assigned to ticket in database
-	if err := dbclient.Client.Tickets.SetPanelId(ctx.GuildId(), ticket.Id, panelId); err != nil {
+	if err := logic.SetTicketPanel(ctx, ticket, panel); err != nil {
 		ctx.HandleError(err)
 		return
 	}
@@ -152,7 +152,13 @@ func (SwitchPanelCommand) Execute(ctx registry.CommandContext, panelId int) {
 				return
 			}
 
-			msg := logic.BuildJoinThreadMessage(ctx.Worker(), ctx.GuildId(), ticket.UserId, ticket.Id, &panel, threadStaff, ctx.PremiumTier())
+			claimedBy, err := dbclient.Client.TicketClaims.Get(ctx.GuildId(), ticket.Id)
+			if err != nil {
+				sentry.ErrorWithContext(err, ctx.ToErrorContext()) // Only log
+				return
+			}
+
+			msg := logic.BuildJoinThreadMessage(ctx.Worker(), ctx.GuildId(), ticket.UserId, ticket.Id, &panel, threadStaff, claimedBy, ctx.PremiumTier())
 			if _, err := ctx.Worker().EditMessage(*settings.TicketNotificationChannel, *ticket.JoinMessageId, msg.IntoEditMessageData()); err != nil {
 				sentry.ErrorWithContext(err, ctx.ToErrorContext()) // Only log
 				return
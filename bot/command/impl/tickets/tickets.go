@@ -0,0 +1,44 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type TicketsCommand struct {
+}
+
+func (TicketsCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "tickets",
+		Description:     i18n.HelpTickets,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Support,
+		Children: []registry.Command{
+			TicketsListCommand{},
+			TicketsSearchCommand{},
+		},
+		Category: command.Tickets,
+	}
+}
+
+func (c TicketsCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (TicketsCommand) Execute(ctx registry.CommandContext) {
+	usageEmbed := embed.EmbedField{
+		Name:   "Usage",
+		Value:  "`/tickets list`\n`/tickets search`",
+		Inline: false,
+	}
+
+	ctx.ReplyWithFields(customisation.Red, i18n.Error, i18n.MessageInvalidArgument, utils.ToSlice(usageEmbed))
+	ctx.Reject()
+}
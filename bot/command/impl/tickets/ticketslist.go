@@ -0,0 +1,98 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/gofrs/uuid"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type TicketsListCommand struct {
+}
+
+func (c TicketsListCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "list",
+		Description:     i18n.HelpTicketsList,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Support,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewOptionalArgument("open", "Whether to list open or closed tickets (defaults to open)", interaction.OptionTypeBoolean, "infallible"),
+			command.NewOptionalAutocompleteableArgument("panel", "Only list tickets opened from this panel", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, c.AutoCompleteHandler),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c TicketsListCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (TicketsListCommand) Execute(ctx registry.CommandContext, open *bool, panelId *int) {
+	filter := redis.TicketListFilter{
+		Open:    open,
+		PanelId: panelId,
+	}
+
+	if filter.Open == nil {
+		filter.Open = utils.Ptr(true)
+	}
+
+	if panelId != nil && !isGuildPanel(ctx, *panelId) {
+		return
+	}
+
+	replyWithTicketList(ctx, filter)
+}
+
+func (TicketsListCommand) AutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	return utils.AutoCompletePanels(data, value)
+}
+
+// Replies with an error if the panel does not exist, or belongs to another guild
+func isGuildPanel(ctx registry.CommandContext, panelId int) bool {
+	panel, err := dbclient.Client.Panel.GetById(panelId)
+	if err != nil {
+		ctx.HandleError(err)
+		return false
+	}
+
+	if panel.PanelId == 0 || panel.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageSwitchPanelInvalidPanel)
+		return false
+	}
+
+	return true
+}
+
+func replyWithTicketList(ctx registry.CommandContext, filter redis.TicketListFilter) {
+	// The filter is stored so that the page buttons can retrieve it
+	id, err := uuid.NewV4()
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if err := redis.StoreTicketListFilter(ctx.GuildId(), id.String(), filter); err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	res, err := logic.BuildTicketListResponse(ctx, id.String(), filter, 0)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if _, err := ctx.ReplyWith(res); err != nil {
+		ctx.HandleError(err)
+	}
+}
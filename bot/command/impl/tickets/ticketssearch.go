@@ -0,0 +1,195 @@
+package tickets
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/objects/interaction"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TicketsSearchCommand struct {
+}
+
+func (c TicketsSearchCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "search",
+		Description:     i18n.HelpTicketsSearch,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Support,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewOptionalAutocompleteableArgument("ticket_id", "View a single ticket by its ID", interaction.OptionTypeInteger, i18n.MessageTicketSearchInvalidTicket, c.TicketAutoCompleteHandler),
+			command.NewOptionalArgument("user", "Only show tickets opened by this user", interaction.OptionTypeUser, i18n.MessageInvalidUser),
+			command.NewOptionalArgument("open", "Only show open (true) or closed (false) tickets", interaction.OptionTypeBoolean, "infallible"),
+			command.NewOptionalAutocompleteableArgument("panel", "Only show tickets opened from this panel", interaction.OptionTypeInteger, i18n.MessageSwitchPanelInvalidPanel, c.PanelAutoCompleteHandler),
+			command.NewOptionalArgument("claimed_by", "Only show tickets claimed by this user", interaction.OptionTypeUser, i18n.MessageInvalidUser),
+			command.NewOptionalArgument("from", "Only show tickets opened on or after this date (YYYY-MM-DD)", interaction.OptionTypeString, i18n.MessageTicketSearchInvalidDate),
+			command.NewOptionalArgument("to", "Only show tickets opened on or before this date (YYYY-MM-DD)", interaction.OptionTypeString, i18n.MessageTicketSearchInvalidDate),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c TicketsSearchCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (TicketsSearchCommand) Execute(ctx registry.CommandContext, ticketId *int, userId *uint64, open *bool, panelId *int, claimedBy *uint64, from, to *string) {
+	if ticketId != nil {
+		showTicket(ctx, *ticketId)
+		return
+	}
+
+	filter := redis.TicketListFilter{
+		UserId:    userId,
+		Open:      open,
+		PanelId:   panelId,
+		ClaimedBy: claimedBy,
+	}
+
+	if panelId != nil && !isGuildPanel(ctx, *panelId) {
+		return
+	}
+
+	if from != nil {
+		date, err := time.Parse("2006-01-02", *from)
+		if err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTicketSearchInvalidDate)
+			return
+		}
+
+		filter.From = &date
+	}
+
+	if to != nil {
+		date, err := time.Parse("2006-01-02", *to)
+		if err != nil {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTicketSearchInvalidDate)
+			return
+		}
+
+		// Include tickets opened at any point on the given day
+		date = date.Add(time.Hour*24 - time.Nanosecond)
+		filter.To = &date
+	}
+
+	replyWithTicketList(ctx, filter)
+}
+
+func showTicket(ctx registry.CommandContext, ticketId int) {
+	ticket, err := dbclient.Client.Tickets.Get(ticketId, ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if ticket.Id == 0 || ticket.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTicketSearchInvalidTicket)
+		return
+	}
+
+	claimedBy, err := dbclient.Client.TicketClaims.Get(ticket.GuildId, ticket.Id)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	panelName := "None"
+	if ticket.PanelId != nil {
+		panel, err := dbclient.Client.Panel.GetById(*ticket.PanelId)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		if panel.PanelId != 0 {
+			panelName = panel.Title
+		}
+	}
+
+	// TODO: Translate titles
+	fields := []embed.EmbedField{
+		utils.EmbedFieldRaw("Opened By", fmt.Sprintf("<@%d>", ticket.UserId), true),
+		utils.EmbedFieldRaw("Panel", panelName, true),
+		utils.EmbedFieldRaw("Status", logic.TicketStatus(ctx, ticket), true),
+		utils.EmbedFieldRaw("Open Time", message.BuildTimestamp(ticket.OpenTime, message.TimestampStyleShortDateTime), true),
+	}
+
+	if ticket.CloseTime != nil {
+		fields = append(fields, utils.EmbedFieldRaw("Close Time", message.BuildTimestamp(*ticket.CloseTime, message.TimestampStyleShortDateTime), true))
+	}
+
+	if claimedBy != 0 {
+		fields = append(fields, utils.EmbedFieldRaw("Claimed By", fmt.Sprintf("<@%d>", claimedBy), true))
+	}
+
+	if ticket.Open && ticket.ChannelId != nil {
+		fields = append(fields, utils.EmbedFieldRaw("Channel", fmt.Sprintf("<#%d>", *ticket.ChannelId), true))
+	} else if ticket.HasTranscript {
+		fields = append(fields, utils.EmbedFieldRaw("Transcript", fmt.Sprintf("[%s](%s)", ctx.GetMessage(i18n.MessageTicketListTranscript), logic.TranscriptUrl(ticket.GuildId, ticket.Id)), true))
+	}
+
+	e := utils.BuildEmbedRaw(ctx.GetColour(customisation.Green), fmt.Sprintf("%s #%d", ctx.GetMessage(i18n.Ticket), ticket.Id), "", fields, ctx.PremiumTier())
+	if _, err := ctx.ReplyWith(command.NewEphemeralEmbedMessageResponse(e)); err != nil {
+		ctx.HandleError(err)
+	}
+}
+
+func (TicketsSearchCommand) TicketAutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	if data.GuildId.Value == 0 {
+		return nil
+	}
+
+	// Only the most recent tickets are suggested, but any ID can still be entered manually
+	tickets, err := dbclient.Client.Tickets.GetByOptions(database.TicketQueryOptions{
+		GuildId: data.GuildId.Value,
+		Order:   database.OrderTypeDescending,
+		Limit:   100,
+	})
+
+	if err != nil {
+		sentry.Error(err) // TODO: Context
+		return nil
+	}
+
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, ticket := range tickets {
+		if len(choices) >= 25 {
+			break
+		}
+
+		if !strings.HasPrefix(strconv.Itoa(ticket.Id), value) {
+			continue
+		}
+
+		status := "closed"
+		if ticket.Open {
+			status = "open"
+		}
+
+		choices = append(choices, interaction.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("#%d (%s, opened %s)", ticket.Id, status, ticket.OpenTime.Format("2006-01-02")),
+			Value: ticket.Id,
+		})
+	}
+
+	return choices
+}
+
+func (TicketsSearchCommand) PanelAutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	return utils.AutoCompletePanels(data, value)
+}
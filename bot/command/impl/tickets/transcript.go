@@ -0,0 +1,43 @@
+package tickets
+
+import (
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/interaction"
+)
+
+type TranscriptCommand struct {
+}
+
+func (TranscriptCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "transcript",
+		Description:     i18n.HelpTranscript,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Everyone,
+		Children: []registry.Command{
+			TranscriptExportCommand{},
+		},
+		Category: command.Tickets,
+	}
+}
+
+func (c TranscriptCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (TranscriptCommand) Execute(ctx registry.CommandContext) {
+	usageEmbed := embed.EmbedField{
+		Name:   "Usage",
+		Value:  "`/transcript export`",
+		Inline: false,
+	}
+
+	ctx.ReplyWithFields(customisation.Red, i18n.Error, i18n.MessageInvalidArgument, utils.ToSlice(usageEmbed))
+	ctx.Reject()
+}
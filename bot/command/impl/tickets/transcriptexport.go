@@ -0,0 +1,190 @@
+package tickets
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/transcripts"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction"
+	"github.com/rxdn/gdl/rest"
+	"github.com/rxdn/gdl/rest/request"
+	"strconv"
+	"strings"
+)
+
+type TranscriptExportCommand struct {
+}
+
+func (c TranscriptExportCommand) Properties() registry.Properties {
+	return registry.Properties{
+		Name:            "export",
+		Description:     i18n.HelpTranscriptExport,
+		Type:            interaction.ApplicationCommandTypeChatInput,
+		PermissionLevel: permission.Everyone,
+		Category:        command.Tickets,
+		Arguments: command.Arguments(
+			command.NewRequiredAutocompleteableArgument("ticket_id", "ID of the closed ticket to export the transcript of", interaction.OptionTypeInteger, i18n.MessageInvalidArgument, c.TicketAutoCompleteHandler),
+			command.NewRequiredAutocompleteableArgument("format", "The format to export the transcript in", interaction.OptionTypeString, i18n.MessageTranscriptExportInvalidFormat, c.FormatAutoCompleteHandler),
+		),
+		InteractionOnly: true,
+	}
+}
+
+func (c TranscriptExportCommand) GetExecutor() interface{} {
+	return c.Execute
+}
+
+func (TranscriptExportCommand) Execute(ctx registry.CommandContext, ticketId int, rawFormat string) {
+	format := transcripts.ExportFormat(strings.ToLower(rawFormat))
+	if !utils.Contains(transcripts.ExportFormats, format) {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTranscriptExportInvalidFormat)
+		return
+	}
+
+	ticket, err := dbclient.Client.Tickets.Get(ticketId, ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if ticket.Id == 0 || ticket.GuildId != ctx.GuildId() {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTranscriptExportNotFound)
+		return
+	}
+
+	// Openers can export their own tickets, and staff can export any ticket they could view
+	hasPermission, err := logic.HasPermissionForTicket(ctx.Worker(), ticket, ctx.UserId())
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if !hasPermission {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTranscriptExportNoPermission)
+		return
+	}
+
+	if ticket.Open || !ticket.HasTranscript {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTranscriptExportNotFound)
+		return
+	}
+
+	transcript, err := utils.TranscriptStore.Get(ctx.GuildId(), ticket.Id)
+	if err != nil {
+		if err == transcripts.ErrNotFound {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTranscriptExportNotFound)
+		} else {
+			ctx.HandleError(err)
+		}
+
+		return
+	}
+
+	encoded, err := transcripts.NewExport(ticket, transcript).Encode(format)
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	// Interaction responses cannot include files, so the export is sent in DMs
+	dmChannel, ok := logic.GetDmChannel(ctx, ctx.UserId())
+	if !ok {
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTranscriptExportDmsDisabled)
+		return
+	}
+
+	guild, err := ctx.Guild()
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	e := utils.BuildEmbedRaw(
+		ctx.GetColour(customisation.Green),
+		ctx.GetMessage(i18n.TitleTranscriptExport),
+		ctx.GetMessage(i18n.MessageTranscriptExportDm, ticket.Id, guild.Name),
+		nil,
+		ctx.PremiumTier(),
+	)
+
+	data := rest.CreateMessageData{
+		Embeds: utils.Slice(e),
+		File: &rest.File{
+			Name:        fmt.Sprintf("transcript-%d.%s", ticket.Id, format),
+			ContentType: exportContentType(format),
+			Reader:      bytes.NewReader(encoded),
+		},
+	}
+
+	if _, err := ctx.Worker().CreateMessageComplex(dmChannel, data); err != nil {
+		if restError, ok := err.(request.RestError); ok && restError.StatusCode == 403 {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTranscriptExportDmsDisabled)
+		} else {
+			ctx.HandleError(err)
+		}
+
+		return
+	}
+
+	ctx.Reply(customisation.Green, i18n.TitleTranscriptExport, i18n.MessageTranscriptExportSuccess, ticket.Id)
+}
+
+func exportContentType(format transcripts.ExportFormat) string {
+	switch format {
+	case transcripts.ExportFormatJson:
+		return "application/json"
+	case transcripts.ExportFormatMarkdown:
+		return "text/markdown"
+	default:
+		return "text/plain"
+	}
+}
+
+func (TranscriptExportCommand) TicketAutoCompleteHandler(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	if data.GuildId.Value == 0 {
+		return nil
+	}
+
+	if data.Member == nil {
+		return nil
+	}
+
+	tickets, err := dbclient.Client.Tickets.GetClosedByUserPrefixed(data.GuildId.Value, data.Member.User.Id, value, 25)
+	if err != nil {
+		sentry.Error(err)
+		return nil
+	}
+
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, ticket := range tickets {
+		if !ticket.HasTranscript {
+			continue
+		}
+
+		choices = append(choices, interaction.ApplicationCommandOptionChoice{
+			Name:  strconv.Itoa(ticket.Id),
+			Value: ticket.Id,
+		})
+	}
+
+	return choices
+}
+
+func (TranscriptExportCommand) FormatAutoCompleteHandler(_ interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, format := range transcripts.ExportFormats {
+		if strings.Contains(string(format), strings.ToLower(value)) {
+			choices = append(choices, utils.StringChoice(string(format)))
+		}
+	}
+
+	return choices
+}
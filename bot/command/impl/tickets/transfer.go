@@ -10,7 +10,6 @@ import (
 	"github.com/TicketsBot/worker/bot/logic"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
-	"github.com/rxdn/gdl/objects/channel"
 	"github.com/rxdn/gdl/objects/interaction"
 )
 
@@ -49,18 +48,6 @@ func (TransferCommand) Execute(ctx registry.CommandContext, userId uint64) {
 		return
 	}
 
-	// Check if thread
-	ch, err := ctx.Worker().GetChannel(ctx.ChannelId())
-	if err != nil {
-		ctx.HandleError(err)
-		return
-	}
-
-	if ch.Type == channel.ChannelTypeGuildPrivateThread {
-		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageClaimThread)
-		return
-	}
-
 	member, err := ctx.Worker().GetGuildMember(ctx.GuildId(), userId)
 	if err != nil {
 		ctx.HandleError(err)
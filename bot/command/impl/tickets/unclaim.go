@@ -2,7 +2,6 @@ package tickets
 
 import (
 	"github.com/TicketsBot/common/permission"
-	"github.com/TicketsBot/database"
 	"github.com/TicketsBot/worker/bot/command"
 	"github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
@@ -10,7 +9,6 @@ import (
 	"github.com/TicketsBot/worker/bot/logic"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/interaction"
-	"github.com/rxdn/gdl/rest"
 )
 
 type UnclaimCommand struct {
@@ -45,12 +43,6 @@ func (UnclaimCommand) Execute(ctx registry.CommandContext) {
 		return
 	}
 
-	// Check if thread
-	if ticket.IsThread {
-		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageClaimThread)
-		return
-	}
-
 	// Get who claimed
 	whoClaimed, err := dbclient.Client.TicketClaims.Get(ctx.GuildId(), ticket.Id)
 	if err != nil {
@@ -76,35 +68,7 @@ func (UnclaimCommand) Execute(ctx registry.CommandContext) {
 		return
 	}
 
-	// Set to unclaimed in DB
-	if err := dbclient.Client.TicketClaims.Delete(ctx.GuildId(), ticket.Id); err != nil {
-		ctx.HandleError(err)
-		return
-	}
-
-	// get panel
-	var panel *database.Panel
-	if ticket.PanelId != nil {
-		var derefPanel database.Panel
-		derefPanel, err = dbclient.Client.Panel.GetById(*ticket.PanelId)
-
-		if derefPanel.PanelId != 0 {
-			panel = &derefPanel
-		}
-	}
-
-	overwrites, err := logic.CreateOverwrites(ctx.Worker(), ctx.GuildId(), ticket.UserId, ctx.Worker().BotId, panel)
-	if err != nil {
-		ctx.HandleError(err)
-		return
-	}
-
-	// Update channel
-	data := rest.ModifyChannelData{
-		PermissionOverwrites: overwrites,
-	}
-
-	if _, err := ctx.Worker().ModifyChannel(ctx.ChannelId(), data); err != nil {
+	if err := logic.UnclaimTicket(ctx, ticket, whoClaimed); err != nil {
 		ctx.HandleError(err)
 		return
 	}
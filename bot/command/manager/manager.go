@@ -53,7 +53,10 @@ func (cm *CommandManager) RegisterCommands() {
 	cm.registry["add"] = tickets.AddCommand{}
 	cm.registry["claim"] = tickets.ClaimCommand{}
 	cm.registry["close"] = tickets.CloseCommand{}
+	cm.registry["closeall"] = tickets.CloseAllCommand{}
 	cm.registry["closerequest"] = tickets.CloseRequestCommand{}
+	cm.registry["label"] = tickets.LabelCommand{}
+	cm.registry["note"] = tickets.NoteCommand{}
 	cm.registry["on-call"] = tickets.OnCallCommand{}
 	cm.registry["open"] = tickets.OpenCommand{}
 	cm.registry["Start Ticket"] = tickets.StartTicketCommand{}
@@ -61,6 +64,8 @@ func (cm *CommandManager) RegisterCommands() {
 	cm.registry["rename"] = tickets.RenameCommand{}
 	cm.registry["reopen"] = tickets.ReopenCommand{}
 	cm.registry["switchpanel"] = tickets.SwitchPanelCommand{}
+	cm.registry["tickets"] = tickets.TicketsCommand{}
+	cm.registry["transcript"] = tickets.TranscriptCommand{}
 	cm.registry["transfer"] = tickets.TransferCommand{}
 	cm.registry["unclaim"] = tickets.UnclaimCommand{}
 }
@@ -61,3 +61,10 @@ type CommandContext interface {
 
 	IsBlacklisted() (bool, error)
 }
+
+// InteractionContext is implemented by contexts created from an interaction. The token can be used to send follow up
+// messages for 15 minutes after the interaction was received.
+type InteractionContext interface {
+	CommandContext
+	InteractionToken() string
+}
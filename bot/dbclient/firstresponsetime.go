@@ -0,0 +1,28 @@
+package dbclient
+
+import (
+	"context"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+// GetAverageFirstResponseTime returns the mean first response time of the tickets, or nil if none of them have been
+// responded to. The database package only provides guild and user wide averages.
+func GetAverageFirstResponseTime(guildId uint64, ticketIds []int) (responseTime *time.Duration, e error) {
+	if len(ticketIds) == 0 {
+		return nil, nil
+	}
+
+	array := &pgtype.Int4Array{}
+	if err := array.Set(ticketIds); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT AVG(response_time) FROM first_response_time WHERE "guild_id" = $1 AND "ticket_id" = ANY($2);`
+	if err := Pool.QueryRow(context.Background(), query, guildId, array).Scan(&responseTime); err != nil && err != pgx.ErrNoRows {
+		e = err
+	}
+
+	return
+}
@@ -0,0 +1,116 @@
+package dbclient
+
+import (
+	"context"
+)
+
+// GetOpenClaimCounts returns the number of open tickets that each member has claimed
+func GetOpenClaimCounts(guildId uint64) (map[uint64]int, error) {
+	query := `
+SELECT ticket_claims.user_id, COUNT(*)
+FROM ticket_claims
+INNER JOIN tickets
+ON ticket_claims.guild_id = tickets.guild_id AND ticket_claims.ticket_id = tickets.id
+WHERE ticket_claims.guild_id = $1 AND tickets.open = true
+GROUP BY ticket_claims.user_id;`
+
+	rows, err := Pool.Query(context.Background(), query, guildId)
+	defer rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint64]int)
+	for rows.Next() {
+		var userId uint64
+		var count int
+		if err := rows.Scan(&userId, &count); err != nil {
+			return nil, err
+		}
+
+		counts[userId] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetClaimedOpenTicketIds returns the IDs of the guild's open tickets that have been claimed
+func GetClaimedOpenTicketIds(guildId uint64) (map[int]bool, error) {
+	query := `
+SELECT ticket_claims.ticket_id
+FROM ticket_claims
+INNER JOIN tickets
+ON ticket_claims.guild_id = tickets.guild_id AND ticket_claims.ticket_id = tickets.id
+WHERE ticket_claims.guild_id = $1 AND tickets.open = true;`
+
+	rows, err := Pool.Query(context.Background(), query, guildId)
+	defer rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make(map[int]bool)
+	for rows.Next() {
+		var ticketId int
+		if err := rows.Scan(&ticketId); err != nil {
+			return nil, err
+		}
+
+		claimed[ticketId] = true
+	}
+
+	return claimed, rows.Err()
+}
+
+// GetOpenTicketClaimers returns the member that has claimed each of the guild's open tickets. Unclaimed tickets are
+// not included.
+func GetOpenTicketClaimers(guildId uint64) (map[int]uint64, error) {
+	query := `
+SELECT ticket_claims.ticket_id, ticket_claims.user_id
+FROM ticket_claims
+INNER JOIN tickets
+ON ticket_claims.guild_id = tickets.guild_id AND ticket_claims.ticket_id = tickets.id
+WHERE ticket_claims.guild_id = $1 AND tickets.open = true;`
+
+	rows, err := Pool.Query(context.Background(), query, guildId)
+	defer rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	claimers := make(map[int]uint64)
+	for rows.Next() {
+		var ticketId int
+		var userId uint64
+		if err := rows.Scan(&ticketId, &userId); err != nil {
+			return nil, err
+		}
+
+		claimers[ticketId] = userId
+	}
+
+	return claimers, rows.Err()
+}
+
+// GetClaimedTicketIds returns the IDs of every ticket in the guild that the member has claimed, open or closed
+func GetClaimedTicketIds(guildId, userId uint64) (map[int]bool, error) {
+	query := `SELECT "ticket_id" FROM ticket_claims WHERE "guild_id" = $1 AND "user_id" = $2;`
+
+	rows, err := Pool.Query(context.Background(), query, guildId, userId)
+	defer rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make(map[int]bool)
+	for rows.Next() {
+		var ticketId int
+		if err := rows.Scan(&ticketId); err != nil {
+			return nil, err
+		}
+
+		claimed[ticketId] = true
+	}
+
+	return claimed, rows.Err()
+}
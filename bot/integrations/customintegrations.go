@@ -10,6 +10,7 @@ import (
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/config"
 	"net/http"
+	neturl "net/url"
 	"strconv"
 	"strings"
 )
@@ -23,6 +24,9 @@ var (
 	}
 
 	ErrIntegrationReturnedErrorStatus = errors.New("Integration returned an error status")
+
+	// Prevent form answers from injecting additional headers
+	headerValueReplacer = strings.NewReplacer("\r", " ", "\n", " ")
 )
 
 type integrationWebhookBody struct {
@@ -38,6 +42,7 @@ func Fetch(
 	secrets []database.SecretWithValue,
 	headers []database.CustomIntegrationHeader,
 	placeholders []database.CustomIntegrationPlaceholder, // Only include placeholders that are actually used
+	formAnswers map[string]string, // Keyed by placeholder name
 ) (map[string]string, error) {
 	prometheus.LogIntegrationRequest(integration.Id, ticket.GuildId)
 
@@ -47,6 +52,11 @@ func Fetch(
 		url = strings.ReplaceAll(url, "%"+secret.Name+"%", secret.Value)
 	}
 
+	// Answers are user input, so must not be able to change the structure of the URL
+	url = utils.ReplaceFormPlaceholders(url, func(name string) string {
+		return neturl.QueryEscape(formAnswers[name])
+	})
+
 	// Apply headers
 	headerMap := make(map[string]string)
 	for _, header := range headers {
@@ -61,6 +71,10 @@ func Fetch(
 			value = strings.ReplaceAll(value, "%"+secret.Name+"%", secret.Value)
 		}
 
+		value = utils.ReplaceFormPlaceholders(value, func(name string) string {
+			return headerValueReplacer.Replace(formAnswers[name])
+		})
+
 		headerMap[header.Name] = value
 	}
 
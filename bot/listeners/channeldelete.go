@@ -4,10 +4,21 @@ import (
 	"github.com/TicketsBot/common/sentry"
 	"github.com/TicketsBot/worker"
 	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
 	"github.com/rxdn/gdl/gateway/payloads/events"
 )
 
 func OnChannelDelete(worker *worker.Context, e *events.ChannelDelete) {
+	// if this is a ticket channel that was deleted without being closed, its messages are no longer needed
+	ticket, err := dbclient.Client.Tickets.GetByChannel(e.Id)
+	if err != nil {
+		sentry.Error(err)
+	} else if ticket.Id != 0 && ticket.Open {
+		if err := redis.DeleteTranscriptLog(ticket.GuildId, ticket.Id); err != nil {
+			sentry.Error(err)
+		}
+	}
+
 	// if this is an ticket channel, close it
 	if err := dbclient.Client.Tickets.CloseByChannel(e.Id); err != nil {
 		sentry.Error(err)
@@ -36,6 +36,18 @@ func OnMessage(worker *worker.Context, e *events.MessageCreate) {
 		return
 	}
 
+	// log all messages, including our own, for the transcript and the message log
+	event := redis.TranscriptLogEvent{
+		Type:      redis.TranscriptLogEventCreate,
+		MessageId: e.Id,
+		Message:   &e.Message,
+		Time:      time.Now(),
+	}
+
+	if err := redis.AppendTranscriptLog(e.GuildId, ticket.Id, event); err != nil {
+		sentry.ErrorWithContext(err, utils.MessageCreateErrorContext(e))
+	}
+
 	// ignore our own messages
 	if e.Author.Id != worker.BotId && !e.Author.Bot {
 		// set participants, for logging
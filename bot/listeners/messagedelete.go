@@ -0,0 +1,143 @@
+package listeners
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/activity"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/errorcontext"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/rxdn/gdl/gateway/payloads/events"
+	"github.com/rxdn/gdl/objects/auditlog"
+	"github.com/rxdn/gdl/objects/user"
+	"github.com/rxdn/gdl/rest"
+	"time"
+)
+
+// Audit log entries for message deletions are grouped, and only created when a user deletes another user's message
+const messageDeleteAuditLogWindow = time.Minute * 5
+
+// OnMessageDelete logs messages deleted from ticket channels, so the transcript can show who deleted them, and posts
+// the deleted content to the message log channel, if the guild has one
+func OnMessageDelete(worker *worker.Context, e *events.MessageDelete) {
+	// ignore DMs
+	if e.GuildId == 0 {
+		return
+	}
+
+	errorContext := errorcontext.WorkerErrorContext{
+		Guild:   e.GuildId,
+		Channel: e.ChannelId,
+	}
+
+	ticket, err := dbclient.Client.Tickets.GetByChannelAndGuild(e.ChannelId, e.GuildId)
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+		return
+	}
+
+	if ticket.Id == 0 {
+		return
+	}
+
+	// Messages that were not logged, e.g. because the ticket has no log, cannot be shown in the transcript or the
+	// message log, so there is no need to find out who deleted them
+	logged, err := redis.GetLoggedMessage(e.GuildId, ticket.Id, e.Id)
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+		return
+	}
+
+	if logged == nil {
+		return
+	}
+
+	event := redis.TranscriptLogEvent{
+		Type:      redis.TranscriptLogEventDelete,
+		MessageId: e.Id,
+		Time:      time.Now(),
+	}
+
+	// The bot's own messages, such as the mention ping sent on open, are almost always deleted by the bot itself, so
+	// the audit log is only checked for other users' messages. The bot may not have permission to view the audit log,
+	// in which case we just don't know who deleted the message.
+	if logged.Author.Id != worker.BotId {
+		if deletedBy, target, ok := findMessageDeleter(worker, e.GuildId, e.ChannelId); ok {
+			event.DeletedBy = &deletedBy
+			event.DeletedByTarget = target
+		}
+	}
+
+	if err := redis.AppendTranscriptLog(e.GuildId, ticket.Id, event); err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+	}
+
+	// Bots' messages are not worth logging
+	if !shouldLogMessage(*logged) {
+		return
+	}
+
+	logChannel, err := redis.GetMessageLogChannel(e.GuildId)
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+		return
+	}
+
+	if logChannel == nil {
+		return
+	}
+
+	// Only show who deleted the message if the audit log entry is for this message's author
+	var deletedBy *user.User
+	if event.DeletedBy != nil && event.DeletedByTarget == logged.Author.Id {
+		deletedBy = event.DeletedBy
+	}
+
+	if err := activity.SendLogEmbed(worker, *logChannel, buildMessageDeleteEmbed(ticket, *logged, deletedBy)); err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+	}
+}
+
+// Returns the user who deleted a message in the channel since the last time the audit log was checked, and the author
+// of the message they deleted. Entries are reused for repeated deletions by the same user, of messages by the same
+// author, so an entry is only attributed if it is new, or its count has gone up.
+func findMessageDeleter(worker *worker.Context, guildId, channelId uint64) (user.User, uint64, bool) {
+	auditLog, err := worker.GetGuildAuditLog(guildId, rest.GetGuildAuditLogData{
+		ActionType: auditlog.EventMessageDelete,
+		Limit:      10,
+	})
+
+	if err != nil {
+		return user.User{}, 0, false
+	}
+
+	for _, entry := range auditLog.Entries {
+		if entry.Options.ChannelId != channelId {
+			continue
+		}
+
+		if time.Since(utils.SnowflakeToTime(entry.Id)) > messageDeleteAuditLogWindow {
+			continue
+		}
+
+		// Entries may be updated after they are created, so are kept for longer than the window
+		previous, err := redis.SwapMessageDeleteAuditLogCount(entry.Id, entry.Options.Count, messageDeleteAuditLogWindow*2)
+		if err != nil {
+			sentry.Error(err)
+			continue
+		}
+
+		if previous != nil && *previous >= entry.Options.Count {
+			continue
+		}
+
+		for _, u := range auditLog.Users {
+			if u.Id == entry.UserId {
+				return u, entry.TargetId, true
+			}
+		}
+	}
+
+	return user.User{}, 0, false
+}
@@ -0,0 +1,102 @@
+package listeners
+
+import (
+	"fmt"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/objects/user"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Only messages sent by users are logged, as bots edit their own messages constantly
+func shouldLogMessage(msg message.Message) bool {
+	return msg.Author.Id != 0 && !msg.Author.Bot && msg.WebhookId == 0
+}
+
+func isMessageChanged(previous, msg message.Message) bool {
+	if previous.Content != msg.Content || len(previous.Attachments) != len(msg.Attachments) {
+		return true
+	}
+
+	for i, attachment := range msg.Attachments {
+		if previous.Attachments[i].Url != attachment.Url {
+			return true
+		}
+	}
+
+	return false
+}
+
+func buildMessageEditEmbed(ticket database.Ticket, msg message.Message, previous *message.Message) *embed.Embed {
+	messageLink := fmt.Sprintf("https://discord.com/channels/%d/%d/%d", msg.GuildId, msg.ChannelId, msg.Id)
+
+	e := embed.NewEmbed().
+		SetTitle("Message Edited").
+		SetColor(customisation.GetColourOrDefault(ticket.GuildId, customisation.Orange)).
+		SetDescription(fmt.Sprintf("[Jump to message](%s)", messageLink)).
+		SetTimestamp(msg.Timestamp).
+		AddField("Ticket", fmt.Sprintf("#%d (<#%d>)", ticket.Id, msg.ChannelId), true).
+		AddField("Author", fmt.Sprintf("<@%d>", msg.Author.Id), true)
+
+	if msg.EditedTimestamp != nil {
+		e.SetTimestamp(*msg.EditedTimestamp)
+	}
+
+	if previous == nil {
+		e.AddField("Before", "Content not available", false)
+	} else {
+		e.AddField("Before", formatMessageContent(*previous), false)
+	}
+
+	e.AddField("After", formatMessageContent(msg), false)
+
+	return e
+}
+
+func buildMessageDeleteEmbed(ticket database.Ticket, msg message.Message, deletedBy *user.User) *embed.Embed {
+	e := embed.NewEmbed().
+		SetTitle("Message Deleted").
+		SetColor(customisation.GetColourOrDefault(ticket.GuildId, customisation.Red)).
+		SetTimestamp(time.Now()).
+		AddField("Ticket", fmt.Sprintf("#%d (<#%d>)", ticket.Id, msg.ChannelId), true).
+		AddField("Author", fmt.Sprintf("<@%d>", msg.Author.Id), true)
+
+	if deletedBy != nil {
+		e.AddField("Deleted By", fmt.Sprintf("<@%d>", deletedBy.Id), true)
+	}
+
+	e.AddField("Message ID", strconv.FormatUint(msg.Id, 10), true)
+	e.AddField("Content", formatMessageContent(msg), false)
+
+	return e
+}
+
+func formatMessageContent(msg message.Message) string {
+	content := msg.Content
+	if len(msg.Attachments) > 0 {
+		attachments := make([]string, len(msg.Attachments))
+		for i, attachment := range msg.Attachments {
+			attachments[i] = attachment.Url
+		}
+
+		if content != "" {
+			content += "\n\n"
+		}
+
+		content += strings.Join(attachments, "\n")
+	}
+
+	if content == "" {
+		return "No content"
+	}
+
+	if len(content) > 1024 {
+		return content[:1021] + "..."
+	}
+
+	return content
+}
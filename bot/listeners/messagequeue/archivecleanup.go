@@ -0,0 +1,60 @@
+package messagequeue
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/cache"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/errorcontext"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/rest/request"
+	"time"
+)
+
+const archiveCleanupInterval = time.Minute
+
+// ListenArchiveCleanup deletes closed ticket channels once their archive retention period has passed
+func ListenArchiveCleanup() {
+	for range time.NewTicker(archiveCleanupInterval).C {
+		deletions, err := redis.TakeDuePendingArchiveDeletions(time.Now(), 100)
+		if err != nil {
+			sentry.Error(err)
+		}
+
+		for _, deletion := range deletions {
+			deletion := deletion
+
+			go func() {
+				errorContext := errorcontext.WorkerErrorContext{
+					Guild:   deletion.GuildId,
+					Channel: deletion.ChannelId,
+				}
+
+				ticket, err := dbclient.Client.Tickets.Get(deletion.TicketId, deletion.GuildId)
+				if err != nil {
+					sentry.ErrorWithContext(err, errorContext)
+					return
+				}
+
+				// Ticket has since been deleted, or the channel has been reused
+				if ticket.Id == 0 || ticket.Open || ticket.ChannelId == nil || *ticket.ChannelId != deletion.ChannelId {
+					return
+				}
+
+				worker, err := buildContext(ticket, cache.Client)
+				if err != nil {
+					sentry.ErrorWithContext(err, errorContext)
+					return
+				}
+
+				if _, err := worker.DeleteChannel(deletion.ChannelId); err != nil {
+					// Channel was already deleted manually
+					if restError, ok := err.(request.RestError); ok && restError.StatusCode == 404 {
+						return
+					}
+
+					sentry.ErrorWithContext(err, errorContext)
+				}
+			}()
+		}
+	}
+}
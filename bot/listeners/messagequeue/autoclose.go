@@ -11,6 +11,7 @@ import (
 	"github.com/TicketsBot/worker/bot/redis"
 	"github.com/TicketsBot/worker/bot/utils"
 	gdlUtils "github.com/rxdn/gdl/utils"
+	"time"
 )
 
 const AutoCloseReason = "Automatically closed due to inactivity"
@@ -43,6 +44,38 @@ func ListenAutoClose() {
 				return
 			}
 
+			// The inactivity timer is paused while support is closed, so tickets are not closed for inactivity that happened
+			// overnight or on holidays. The ticket will be offered again on the next sweep.
+			hours, ok, err := redis.GetBusinessHours(ticket.GuildId, ticket.PanelId)
+			if err != nil {
+				sentry.Error(err)
+				return
+			}
+
+			if ok {
+				now := time.Now()
+
+				isOpen, err := logic.IsWithinBusinessHours(hours, now)
+				if err != nil {
+					sentry.Error(err)
+					return
+				}
+
+				if !isOpen {
+					return
+				}
+
+				isDue, err := logic.IsAutoCloseDue(ticket, hours, now)
+				if err != nil {
+					sentry.Error(err)
+					return
+				}
+
+				if !isDue {
+					return
+				}
+			}
+
 			// get premium status
 			premiumTier, err := utils.PremiumClient.GetTierByGuildId(ticket.GuildId, true, worker.Token, worker.RateLimiter)
 			if err != nil {
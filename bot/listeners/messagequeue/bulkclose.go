@@ -0,0 +1,175 @@
+package messagequeue
+
+import (
+	"github.com/TicketsBot/common/premium"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/cache"
+	"github.com/TicketsBot/worker/bot/command/context"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/errorcontext"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/rest"
+	"time"
+)
+
+const (
+	BulkCloseReason = "Closed in bulk by an administrator"
+
+	// Delay between each ticket being closed, to avoid exhausting the guild's rate limits
+	bulkCloseInterval = time.Second * 2
+
+	// How many tickets to close between each progress message update
+	bulkCloseProgressInterval = 10
+
+	// How often to check for jobs that were being run by a worker that has since died
+	bulkCloseRequeueInterval = time.Minute
+)
+
+func ListenBulkClose() {
+	go requeueAbandonedBulkCloseJobs()
+
+	for {
+		job, err := redis.TakeBulkCloseJob()
+		if err != nil {
+			sentry.Error(err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		go runBulkCloseJob(job)
+	}
+}
+
+func requeueAbandonedBulkCloseJobs() {
+	for {
+		time.Sleep(bulkCloseRequeueInterval)
+
+		if _, err := redis.RequeueAbandonedBulkCloseJobs(); err != nil {
+			sentry.Error(err)
+		}
+	}
+}
+
+// Jobs are removed from the queue and the lock released once they finish, including if they fail. If the worker dies,
+// the lock stops being refreshed, and the job is requeued with a new token once it expires. Tickets are checked to
+// still be open before closing them, so resuming a job is safe.
+func runBulkCloseJob(job redis.BulkCloseJob) {
+	// A requeued job's lock has expired, and another job may have been started for the guild since. If so, the job is
+	// left in the processing list, and requeued once the other job has finished.
+	resumed, err := redis.ResumeBulkCloseLock(job.GuildId, job.Token)
+	if err != nil {
+		sentry.Error(err)
+		return
+	}
+
+	if !resumed {
+		return
+	}
+
+	// Once the lock is lost, the job belongs to whichever worker it is requeued to
+	var lockLost bool
+	defer func() {
+		if lockLost {
+			return
+		}
+
+		if err := redis.CompleteBulkCloseJob(job); err != nil {
+			sentry.Error(err)
+		}
+
+		if err := redis.ReleaseBulkCloseLock(job.GuildId, job.Token); err != nil {
+			sentry.Error(err)
+		}
+	}()
+
+	errorContext := errorcontext.WorkerErrorContext{
+		Guild:   job.GuildId,
+		User:    job.UserId,
+		Channel: job.ChannelId,
+	}
+
+	// buildContext only needs the guild ID
+	worker, err := buildContext(database.Ticket{GuildId: job.GuildId}, cache.Client)
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+		return
+	}
+
+	premiumTier, err := utils.PremiumClient.GetTierByGuildId(job.GuildId, true, worker.Token, worker.RateLimiter)
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+		return
+	}
+
+	reason := BulkCloseReason
+	for i, ticketId := range job.TicketIds {
+		if i > 0 {
+			time.Sleep(bulkCloseInterval)
+		}
+
+		if i > 0 && i%bulkCloseProgressInterval == 0 {
+			updateBulkCloseProgress(worker, job, premiumTier, i, false)
+		}
+
+		locked, err := redis.RefreshBulkCloseLock(job.GuildId, job.Token)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorContext)
+		} else if !locked {
+			lockLost = true
+			return
+		}
+
+		ticket, err := dbclient.Client.Tickets.Get(ticketId, job.GuildId)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorContext)
+			continue
+		}
+
+		// Ticket may have been closed since the job was queued
+		if ticket.Id == 0 || !ticket.Open {
+			continue
+		}
+
+		// If the channel was never created, there is nothing to delete
+		if ticket.ChannelId == nil {
+			if err := dbclient.Client.Tickets.Close(ticket.Id, ticket.GuildId); err != nil {
+				sentry.ErrorWithContext(err, errorContext)
+			}
+
+			continue
+		}
+
+		ctx := context.NewDashboardContext(worker, ticket.GuildId, *ticket.ChannelId, job.UserId, premiumTier)
+		logic.CloseTicket(&ctx, &reason, false)
+	}
+
+	updateBulkCloseProgress(worker, job, premiumTier, len(job.TicketIds), true)
+}
+
+func updateBulkCloseProgress(worker *worker.Context, job redis.BulkCloseJob, premiumTier premium.PremiumTier, closed int, finished bool) {
+	colour := customisation.Orange
+	messageId := i18n.MessageCloseAllProgress
+	if finished {
+		colour = customisation.Green
+		messageId = i18n.MessageCloseAllComplete
+	}
+
+	e := utils.BuildEmbedRaw(
+		customisation.GetColourOrDefault(job.GuildId, colour),
+		i18n.TitleCloseAll.GetFromGuild(job.GuildId),
+		messageId.GetFromGuild(job.GuildId, closed, len(job.TicketIds)),
+		nil,
+		premiumTier,
+	)
+
+	// The progress message may have been deleted, or have been sent in a ticket that has now been closed
+	_, _ = worker.EditMessage(job.ChannelId, job.ProgressMessageId, rest.EditMessageData{
+		Embeds: utils.Slice(e),
+	})
+}
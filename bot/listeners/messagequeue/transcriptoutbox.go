@@ -0,0 +1,31 @@
+package messagequeue
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/errorcontext"
+	"github.com/TicketsBot/worker/bot/logic"
+	"github.com/TicketsBot/worker/bot/redis"
+	"time"
+)
+
+const transcriptOutboxInterval = time.Minute
+
+// ListenTranscriptOutbox retries uploads of transcripts that failed to upload when the ticket was closed, and preserves
+// the attachments of any that were not processed by the worker that closed the ticket
+func ListenTranscriptOutbox() {
+	for range time.NewTicker(transcriptOutboxInterval).C {
+		entries, err := redis.TakeDueTranscriptOutbox(time.Now(), 25)
+		if err != nil {
+			sentry.Error(err)
+		}
+
+		// Uploaded one at a time, as the archiver is likely to be the reason the uploads failed
+		for _, entry := range entries {
+			if err := logic.RetryTranscriptUpload(entry); err != nil {
+				sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{
+					Guild: entry.GuildId,
+				})
+			}
+		}
+	}
+}
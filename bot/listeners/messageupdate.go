@@ -0,0 +1,78 @@
+package listeners
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/activity"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/errorcontext"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/gateway/payloads/events"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"time"
+)
+
+// OnMessageUpdate logs edits to messages in ticket channels, so the transcript can show the edit history, and posts
+// them to the message log channel, if the guild has one. The previous content comes from the transcript log.
+func OnMessageUpdate(worker *worker.Context, e *events.MessageUpdate) {
+	// ignore DMs
+	if e.GuildId == 0 {
+		return
+	}
+
+	errorContext := errorcontext.WorkerErrorContext{
+		Guild:   e.GuildId,
+		Channel: e.ChannelId,
+	}
+
+	ticket, err := dbclient.Client.Tickets.GetByChannelAndGuild(e.ChannelId, e.GuildId)
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+		return
+	}
+
+	if ticket.Id == 0 {
+		return
+	}
+
+	// The previous version has to be read before this update is added to the log
+	var logChannel *uint64
+	var previous *message.Message
+	if shouldLogMessage(e.Message) {
+		logChannel, err = redis.GetMessageLogChannel(e.GuildId)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorContext)
+		} else if logChannel != nil {
+			previous, err = redis.GetLoggedMessage(e.GuildId, ticket.Id, e.Id)
+			if err != nil {
+				sentry.ErrorWithContext(err, errorContext)
+			}
+		}
+	}
+
+	event := redis.TranscriptLogEvent{
+		Type:      redis.TranscriptLogEventUpdate,
+		MessageId: e.Id,
+		Message:   &e.Message,
+		Time:      time.Now(),
+	}
+
+	if err := redis.AppendTranscriptLog(e.GuildId, ticket.Id, event); err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+	}
+
+	if logChannel == nil {
+		return
+	}
+
+	// Updates are also sent when Discord adds embeds for links, without the content changing
+	if previous == nil && e.EditedTimestamp == nil {
+		return
+	} else if previous != nil && !isMessageChanged(*previous, e.Message) {
+		return
+	}
+
+	if err := activity.SendLogEmbed(worker, *logChannel, buildMessageEditEmbed(ticket, e.Message, previous)); err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+	}
+}
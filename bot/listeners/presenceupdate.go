@@ -0,0 +1,30 @@
+package listeners
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/gateway/payloads/events"
+)
+
+// Track which members are offline, so that auto-assignment can skip them. Only received if the gateway has the
+// presence intent. Guilds without auto-assignment are ignored, as nothing would read their presences.
+func OnPresenceUpdate(worker *worker.Context, e *events.PresenceUpdate) {
+	if e.GuildId == 0 || e.User.Id == 0 {
+		return
+	}
+
+	enabled, err := redis.IsAutoAssignEnabled(e.GuildId)
+	if err != nil {
+		sentry.Error(err)
+		return
+	}
+
+	if !enabled {
+		return
+	}
+
+	if err := redis.SetMemberOffline(e.GuildId, e.User.Id, e.Status == string(events.OFFLINE)); err != nil {
+		sentry.Error(err)
+	}
+}
@@ -54,8 +54,14 @@ func OnThreadMembersUpdate(worker *worker.Context, e *events.ThreadMembersUpdate
 			return
 		}
 
+		claimedBy, err := dbclient.Client.TicketClaims.Get(ticket.GuildId, ticket.Id)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{Guild: e.GuildId})
+			return
+		}
+
 		if settings.TicketNotificationChannel != nil {
-			data := logic.BuildJoinThreadMessage(worker, ticket.GuildId, ticket.UserId, ticket.Id, panel, threadStaff, premiumTier)
+			data := logic.BuildJoinThreadMessage(worker, ticket.GuildId, ticket.UserId, ticket.Id, panel, threadStaff, claimedBy, premiumTier)
 			if _, err := worker.EditMessage(*settings.TicketNotificationChannel, *ticket.JoinMessageId, data.IntoEditMessageData()); err != nil {
 				sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{Guild: e.GuildId})
 			}
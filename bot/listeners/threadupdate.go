@@ -66,7 +66,13 @@ func OnThreadUpdate(worker *worker.Context, e *events.ThreadUpdate) {
 				return
 			}
 
-			data := logic.BuildThreadReopenMessage(worker, ticket.GuildId, ticket.UserId, ticket.Id, panel, staffCount, premiumTier)
+			claimedBy, err := dbclient.Client.TicketClaims.Get(ticket.GuildId, ticket.Id)
+			if err != nil {
+				sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{Guild: e.GuildId})
+				return
+			}
+
+			data := logic.BuildThreadReopenMessage(worker, ticket.GuildId, ticket.UserId, ticket.Id, panel, staffCount, claimedBy, premiumTier)
 			msg, err := worker.CreateMessageComplex(*settings.TicketNotificationChannel, data.IntoCreateMessageData())
 			if err != nil {
 				sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{Guild: e.GuildId})
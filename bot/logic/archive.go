@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"fmt"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/permission"
+	"github.com/rxdn/gdl/rest"
+	"time"
+)
+
+// ArchiveTicketChannel moves a closed ticket channel into the guild's archive category instead of deleting it.
+// Returns false if archive mode is disabled, there is no room left in the archive categories, or the channel could not
+// be archived, in which case the channel should be deleted as usual.
+func ArchiveTicketChannel(ctx registry.CommandContext, ticket database.Ticket) (bool, error) {
+	// If an archived ticket is closed again, the channel should be deleted straight away
+	if ticket.ChannelId == nil || !ticket.Open {
+		return false, nil
+	}
+
+	settings, err := redis.GetArchiveCategorySettings(ticket.GuildId)
+	if err != nil {
+		return false, err
+	}
+
+	if !settings.Enabled || settings.CategoryId == 0 {
+		return false, nil
+	}
+
+	category, ok, err := findArchiveCategory(ctx, settings)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	// Strip send permissions from the opener and anyone added to the ticket before the channel is moved, so that it is
+	// never in the archive while they can still write in it. If any step fails, the channel is deleted instead.
+	members, err := dbclient.Client.TicketMembers.Get(ticket.GuildId, ticket.Id)
+	if err != nil {
+		return false, err
+	}
+
+	for _, userId := range append(members, ticket.UserId) {
+		overwrite := channel.PermissionOverwrite{
+			Id:    userId,
+			Type:  channel.PermissionTypeMember,
+			Allow: permission.BuildPermissions(readOnlyAllowed...),
+			Deny:  permission.BuildPermissions(readOnlyDenied...),
+		}
+
+		if err := ctx.Worker().EditChannelPermissions(*ticket.ChannelId, overwrite); err != nil {
+			return false, err
+		}
+	}
+
+	data := rest.ModifyChannelData{
+		Name:     fmt.Sprintf("closed-%d", ticket.Id),
+		ParentId: category,
+	}
+
+	if _, err := ctx.Worker().ModifyChannel(*ticket.ChannelId, data); err != nil {
+		return false, err
+	}
+
+	deletion := redis.PendingArchiveDeletion{
+		GuildId:   ticket.GuildId,
+		TicketId:  ticket.Id,
+		ChannelId: *ticket.ChannelId,
+	}
+
+	// Without a scheduled deletion, the channel would be kept forever
+	deleteAt := time.Now().Add(time.Hour * 24 * time.Duration(settings.RetentionDays))
+	if err := redis.SchedulePendingArchiveDeletion(deletion, deleteAt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Returns the category to move the channel into, or false if both the archive and overflow categories are full
+func findArchiveCategory(ctx registry.CommandContext, settings redis.ArchiveCategorySettings) (uint64, bool, error) {
+	channels, err := ctx.Worker().GetGuildChannels(ctx.GuildId())
+	if err != nil {
+		return 0, false, err
+	}
+
+	if countRealChannels(channels, settings.CategoryId) < 50 {
+		return settings.CategoryId, true, nil
+	}
+
+	if settings.OverflowCategoryId != nil && countRealChannels(channels, *settings.OverflowCategoryId) < 50 {
+		return *settings.OverflowCategoryId, true, nil
+	}
+
+	return 0, false, nil
+}
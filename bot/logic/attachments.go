@@ -0,0 +1,54 @@
+package logic
+
+import (
+	"github.com/TicketsBot/common/premium"
+	"github.com/TicketsBot/worker/bot/transcripts"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/config"
+	"github.com/rxdn/gdl/objects/channel/message"
+)
+
+// Maximum total size of the attachments preserved for a single ticket, in bytes
+const (
+	attachmentLimitFree       = 10 * 1024 * 1024
+	attachmentLimitPremium    = 50 * 1024 * 1024
+	attachmentLimitWhitelabel = 100 * 1024 * 1024
+)
+
+// attachmentPreservationLimit returns the number of bytes of attachments that should be preserved for a ticket, or
+// zero if the store cannot hold attachments.
+func attachmentPreservationLimit(tier premium.PremiumTier) int {
+	if _, ok := utils.TranscriptStore.(transcripts.AttachmentStore); !ok || config.Conf.Transcripts.AttachmentUrl == "" {
+		return 0
+	}
+
+	switch {
+	case tier >= premium.Whitelabel:
+		return attachmentLimitWhitelabel
+	case tier >= premium.Premium:
+		return attachmentLimitPremium
+	default:
+		return attachmentLimitFree
+	}
+}
+
+// preserveAttachments copies the attachments of the transcript to the transcript store, as Discord CDN links stop
+// working once the channel is deleted. Attachments that could not be preserved keep their original URLs.
+func preserveAttachments(guildId uint64, ticketId int, limit int, msgs []message.Message) (int, error) {
+	store, ok := utils.TranscriptStore.(transcripts.AttachmentStore)
+	if !ok || config.Conf.Transcripts.AttachmentUrl == "" {
+		return 0, nil
+	}
+
+	return transcripts.PreserveAttachments(store, config.Conf.Transcripts.AttachmentUrl, guildId, ticketId, msgs, limit)
+}
+
+func hasAttachments(msgs []message.Message) bool {
+	for _, msg := range msgs {
+		if len(msg.Attachments) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
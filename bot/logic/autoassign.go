@@ -0,0 +1,163 @@
+package logic
+
+import (
+	"fmt"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/rest"
+	"sort"
+)
+
+// AutoAssignTicket claims a newly opened ticket on behalf of a staff member, if the guild has auto-assignment enabled.
+// Members who have left the server, or whose last presence update was offline, are skipped. Presence updates are only
+// received if the gateway has the presence intent; without it, every member is treated as online.
+func AutoAssignTicket(ctx registry.CommandContext, ticket database.Ticket, panel *database.Panel) error {
+	if ticket.ChannelId == nil {
+		return nil
+	}
+
+	settings, err := redis.GetAutoAssignSettings(ticket.GuildId)
+	if err != nil {
+		return err
+	}
+
+	if !settings.Enabled {
+		return nil
+	}
+
+	candidates, err := getAutoAssignCandidates(ctx, ticket, panel, settings.Strategy)
+	if err != nil {
+		return err
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	claimCounts, err := dbclient.GetOpenClaimCounts(ticket.GuildId)
+	if err != nil {
+		return err
+	}
+
+	// Remove members who are at their workload cap
+	if settings.WorkloadCap > 0 {
+		var filtered []uint64
+		for _, userId := range candidates {
+			if claimCounts[userId] < settings.WorkloadCap {
+				filtered = append(filtered, userId)
+			}
+		}
+
+		candidates = filtered
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if settings.Strategy == redis.AutoAssignLeastClaims {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return claimCounts[candidates[i]] < claimCounts[candidates[j]]
+		})
+	} else {
+		var panelId int
+		if panel != nil {
+			panelId = panel.PanelId
+		}
+
+		index, err := redis.NextRoundRobinIndex(ticket.GuildId, panelId)
+		if err != nil {
+			return err
+		}
+
+		offset := int(index % int64(len(candidates)))
+		candidates = append(candidates[offset:], candidates[:offset]...)
+	}
+
+	assignee := candidates[0]
+	if err := claimTicket(ctx, ticket, assignee, ctx.Worker().BotId); err != nil {
+		return err
+	}
+
+	data := rest.CreateMessageData{
+		Content: fmt.Sprintf("<@%d>", assignee),
+		Embeds: utils.Slice(utils.BuildEmbedRaw(
+			ctx.GetColour(customisation.Green),
+			ctx.GetMessage(i18n.TitleClaimed),
+			ctx.GetMessage(i18n.MessageAutoAssigned, assignee),
+			nil,
+			ctx.PremiumTier(),
+		)),
+		AllowedMentions: message.AllowedMention{
+			Users: []uint64{assignee},
+		},
+	}
+
+	if _, err := ctx.Worker().CreateMessageComplex(*ticket.ChannelId, data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns staff members eligible to be assigned the ticket, sorted by ID so that round-robin ordering is stable
+func getAutoAssignCandidates(ctx registry.CommandContext, ticket database.Ticket, panel *database.Panel, strategy redis.AutoAssignStrategy) ([]uint64, error) {
+	onCall, err := dbclient.Client.OnCall.GetUsersOnCall(ticket.GuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool []uint64
+	if strategy == redis.AutoAssignOnCall {
+		pool = onCall
+	} else {
+		allowedUsers, _, err := getAllowedUsersRoles(ticket.GuildId, ctx.Worker().BotId, panel)
+		if err != nil {
+			return nil, err
+		}
+
+		// Members who are only staff through roles cannot be listed, but on-call members can
+		pool = append(allowedUsers, onCall...)
+	}
+
+	var candidates []uint64
+	for _, userId := range pool {
+		if userId == ctx.Worker().BotId || userId == ticket.UserId || utils.Contains(candidates, userId) {
+			continue
+		}
+
+		// Also returns an error if the member has left the server
+		hasPermission, err := HasPermissionForTicket(ctx.Worker(), ticket, userId)
+		if err != nil || !hasPermission {
+			continue
+		}
+
+		candidates = append(candidates, userId)
+	}
+
+	offline, err := redis.GetOfflineMembers(ticket.GuildId, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var online []uint64
+	for _, userId := range candidates {
+		if !offline[userId] {
+			online = append(online, userId)
+		}
+	}
+
+	candidates = online
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i] < candidates[j]
+	})
+
+	return candidates, nil
+}
@@ -0,0 +1,339 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const holidayDateFormat = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseBusinessHoursSchedule parses a weekly schedule in the form "mon-fri 09:00-17:00, sat 10:00-14:00". Periods that
+// end before they start, e.g. "fri 22:00-06:00", run overnight into the following day. Holidays are applied by date, so
+// an overnight period that runs into a holiday ends at midnight.
+func ParseBusinessHoursSchedule(s string) ([7][]redis.BusinessHoursPeriod, error) {
+	var days [7][]redis.BusinessHoursPeriod
+
+	for _, entry := range strings.Split(s, ",") {
+		split := strings.Fields(strings.ToLower(entry))
+		if len(split) != 2 {
+			return days, fmt.Errorf("invalid schedule entry \"%s\"", entry)
+		}
+
+		dayRange, err := parseWeekdayRange(split[0])
+		if err != nil {
+			return days, err
+		}
+
+		period, err := parseBusinessHoursPeriod(split[1])
+		if err != nil {
+			return days, err
+		}
+
+		for _, day := range dayRange {
+			if period.End > period.Start {
+				days[day] = append(days[day], period)
+			} else {
+				next := (day + 1) % 7
+				days[day] = append(days[day], redis.BusinessHoursPeriod{Start: period.Start, End: 24 * 60})
+
+				if period.End > 0 {
+					days[next] = append(days[next], redis.BusinessHoursPeriod{Start: 0, End: period.End})
+				}
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// ParseHolidays parses a comma separated list of YYYY-MM-DD dates
+func ParseHolidays(s string) ([]string, error) {
+	var holidays []string
+	for _, date := range strings.Split(s, ",") {
+		date = strings.TrimSpace(date)
+		if date == "" {
+			continue
+		}
+
+		if _, err := time.Parse(holidayDateFormat, date); err != nil {
+			return nil, err
+		}
+
+		holidays = append(holidays, date)
+	}
+
+	return holidays, nil
+}
+
+// Parses "mon" or "mon-fri". Ranges may wrap around the end of the week, e.g. "sat-sun".
+func parseWeekdayRange(s string) ([]time.Weekday, error) {
+	split := strings.SplitN(s, "-", 2)
+
+	start, ok := weekdays[split[0]]
+	if !ok {
+		return nil, fmt.Errorf("invalid day \"%s\"", split[0])
+	}
+
+	if len(split) == 1 {
+		return []time.Weekday{start}, nil
+	}
+
+	end, ok := weekdays[split[1]]
+	if !ok {
+		return nil, fmt.Errorf("invalid day \"%s\"", split[1])
+	}
+
+	days := []time.Weekday{start}
+	for day := start; day != end; {
+		day = (day + 1) % 7
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// Parses "09:00-17:00". The end may be 24:00, or before the start for periods that run overnight.
+func parseBusinessHoursPeriod(s string) (redis.BusinessHoursPeriod, error) {
+	split := strings.Split(s, "-")
+	if len(split) != 2 {
+		return redis.BusinessHoursPeriod{}, fmt.Errorf("invalid time range \"%s\"", s)
+	}
+
+	start, err := parseMinuteOfDay(split[0])
+	if err != nil {
+		return redis.BusinessHoursPeriod{}, err
+	}
+
+	end, err := parseMinuteOfDay(split[1])
+	if err != nil {
+		return redis.BusinessHoursPeriod{}, err
+	}
+
+	if end == start || (end < start && start == 24*60) {
+		return redis.BusinessHoursPeriod{}, fmt.Errorf("time range \"%s\" is empty", s)
+	}
+
+	return redis.BusinessHoursPeriod{
+		Start: start,
+		End:   end,
+	}, nil
+}
+
+func parseMinuteOfDay(s string) (int, error) {
+	split := strings.Split(s, ":")
+	if len(split) != 2 {
+		return 0, fmt.Errorf("invalid time \"%s\"", s)
+	}
+
+	hours, err := strconv.Atoi(split[0])
+	if err != nil {
+		return 0, err
+	}
+
+	minutes, err := strconv.Atoi(split[1])
+	if err != nil {
+		return 0, err
+	}
+
+	minute := hours*60 + minutes
+	if hours < 0 || minutes < 0 || minutes >= 60 || minute > 24*60 {
+		return 0, fmt.Errorf("invalid time \"%s\"", s)
+	}
+
+	return minute, nil
+}
+
+// IsWithinBusinessHours returns whether support is open at the given time
+func IsWithinBusinessHours(hours redis.BusinessHours, now time.Time) (bool, error) {
+	location, err := time.LoadLocation(hours.Timezone)
+	if err != nil {
+		return false, err
+	}
+
+	now = now.In(location)
+	if utils.Contains(hours.Holidays, now.Format(holidayDateFormat)) {
+		return false, nil
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	for _, period := range hours.Days[now.Weekday()] {
+		if minute >= period.Start && minute < period.End {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// OpenDuration returns how long support was open between from and to
+func OpenDuration(hours redis.BusinessHours, from, to time.Time) (time.Duration, error) {
+	location, err := time.LoadLocation(hours.Timezone)
+	if err != nil {
+		return 0, err
+	}
+
+	from, to = from.In(location), to.In(location)
+
+	var open time.Duration
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if utils.Contains(hours.Holidays, day.Format(holidayDateFormat)) {
+			continue
+		}
+
+		for _, period := range hours.Days[day.Weekday()] {
+			start := day.Add(time.Duration(period.Start) * time.Minute)
+			end := day.Add(time.Duration(period.End) * time.Minute)
+
+			if start.Before(from) {
+				start = from
+			}
+
+			if end.After(to) {
+				end = to
+			}
+
+			if end.After(start) {
+				open += end.Sub(start)
+			}
+		}
+	}
+
+	return open, nil
+}
+
+// IsAutoCloseDue returns whether the ticket has been inactive for long enough to be automatically closed, counting
+// only the time that support was open, so that the timer is paused outside of business hours
+func IsAutoCloseDue(ticket database.Ticket, hours redis.BusinessHours, now time.Time) (bool, error) {
+	settings, err := dbclient.Client.AutoClose.Get(ticket.GuildId)
+	if err != nil {
+		return false, err
+	}
+
+	lastMessage, err := dbclient.Client.TicketLastMessage.Get(ticket.GuildId, ticket.Id)
+	if err != nil {
+		return false, err
+	}
+
+	var since time.Time
+	var window *time.Duration
+	if lastMessage.LastMessageTime == nil {
+		since, window = ticket.OpenTime, settings.SinceOpenWithNoResponse
+	} else {
+		since, window = *lastMessage.LastMessageTime, settings.SinceLastMessage
+	}
+
+	// The sweep decided that the ticket is due by a setting that does not apply here, so trust it
+	if window == nil {
+		return true, nil
+	}
+
+	open, err := OpenDuration(hours, since, now)
+	if err != nil {
+		return false, err
+	}
+
+	return open >= *window, nil
+}
+
+// NextOpeningTime returns when support next opens after the given time. Returns false if the schedule has no open
+// periods within the next year.
+func NextOpeningTime(hours redis.BusinessHours, now time.Time) (time.Time, bool, error) {
+	location, err := time.LoadLocation(hours.Timezone)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	now = now.In(location)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+
+	var next *time.Time
+	for i := 0; i <= 366 && next == nil; i++ {
+		day := midnight.AddDate(0, 0, i)
+		if utils.Contains(hours.Holidays, day.Format(holidayDateFormat)) {
+			continue
+		}
+
+		for _, period := range hours.Days[day.Weekday()] {
+			start := day.Add(time.Duration(period.Start) * time.Minute)
+			if start.After(now) && (next == nil || start.Before(*next)) {
+				next = &start
+			}
+		}
+	}
+
+	if next == nil {
+		return time.Time{}, false, nil
+	}
+
+	return *next, true, nil
+}
+
+// CheckBusinessHours returns whether a ticket can be opened from the panel, replying to the user if not. If the ticket
+// can be opened but support is currently closed, an embed telling the user when to expect a response is returned, to be
+// added to the welcome message.
+func CheckBusinessHours(ctx registry.CommandContext, panel *database.Panel) (bool, *embed.Embed, error) {
+	var panelId *int
+	if panel != nil {
+		panelId = &panel.PanelId
+	}
+
+	hours, ok, err := redis.GetBusinessHours(ctx.GuildId(), panelId)
+	if err != nil || !ok {
+		return true, nil, err
+	}
+
+	now := time.Now()
+	isOpen, err := IsWithinBusinessHours(hours, now)
+	if err != nil || isOpen {
+		return true, nil, err
+	}
+
+	nextOpen, hasNext, err := NextOpeningTime(hours, now)
+	if err != nil {
+		return true, nil, err
+	}
+
+	var content string
+	if hasNext {
+		content = ctx.GetMessage(i18n.MessageBusinessHoursClosed, message.BuildTimestamp(nextOpen, message.TimestampStyleLongDateTime))
+	} else {
+		content = ctx.GetMessage(i18n.MessageBusinessHoursClosedIndefinitely)
+	}
+
+	switch hours.Mode {
+	case redis.BusinessHoursModeRefuse:
+		if hours.Message != nil {
+			content = *hours.Message
+		}
+
+		ctx.ReplyRaw(customisation.Red, ctx.GetMessage(i18n.TitleBusinessHours), content)
+		return false, nil, nil
+	case redis.BusinessHoursModeNotice:
+		return true, utils.BuildEmbedRaw(ctx.GetColour(customisation.Orange), ctx.GetMessage(i18n.TitleBusinessHours), content, nil, ctx.PremiumTier()), nil
+	default:
+		return true, nil, errors.New("unknown business hours mode")
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/TicketsBot/common/premium"
 	"github.com/TicketsBot/database"
 	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/activity"
 	"github.com/TicketsBot/worker/bot/command/registry"
-	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
-	"github.com/TicketsBot/worker/i18n"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/rxdn/gdl/objects/channel"
 	"github.com/rxdn/gdl/permission"
 	"github.com/rxdn/gdl/rest"
@@ -18,14 +20,68 @@ import (
 
 // ClaimTicket TODO: Keep /add members
 func ClaimTicket(ctx registry.CommandContext, ticket database.Ticket, userId uint64) error {
+	return claimTicket(ctx, ticket, userId, ctx.UserId())
+}
+
+// UnclaimTicket removes the claim from the ticket, and restores the permissions the ticket had before it was claimed
+func UnclaimTicket(ctx registry.CommandContext, ticket database.Ticket, previousClaimer uint64) error {
+	if err := dbclient.Client.TicketClaims.Delete(ticket.GuildId, ticket.Id); err != nil {
+		return err
+	}
+
+	event := activity.Event{
+		Type:     redis.ActivityEventUnclaim,
+		GuildId:  ticket.GuildId,
+		TicketId: ticket.Id,
+		ActorId:  ctx.UserId(),
+		TargetId: previousClaimer,
+	}
+
+	if ticket.ChannelId != nil {
+		event.ChannelId = *ticket.ChannelId
+	}
+
+	activity.Publish(ctx.Worker(), event)
+
 	if ticket.ChannelId == nil {
-		return errors.New("channel ID is nil")
+		return nil
+	}
+
+	// get panel
+	var panel *database.Panel
+	if ticket.PanelId != nil {
+		derefPanel, err := dbclient.Client.Panel.GetById(*ticket.PanelId)
+		if err != nil {
+			return err
+		}
+
+		if derefPanel.PanelId != 0 {
+			panel = &derefPanel
+		}
 	}
 
-	// Check if thread
 	if ticket.IsThread {
-		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageClaimThread)
-		return nil
+		return UpdateJoinThreadMessage(ctx.Worker(), ticket, panel, 0, ctx.PremiumTier())
+	}
+
+	overwrites, err := CreateOverwrites(ctx.Worker(), ticket.GuildId, ticket.UserId, ctx.Worker().BotId, panel)
+	if err != nil {
+		return err
+	}
+
+	// Update channel
+	data := rest.ModifyChannelData{
+		PermissionOverwrites: overwrites,
+	}
+
+	_, err = ctx.Worker().ModifyChannel(*ticket.ChannelId, data)
+	return err
+}
+
+// actorId is the user to attribute the claim to in the activity log, as tickets may be claimed automatically
+func claimTicket(ctx registry.CommandContext, ticket database.Ticket, userId, actorId uint64) error {
+	if ticket.ChannelId == nil {
+		return errors.New("channel ID is nil")
 	}
 
 	// Get panel
@@ -41,11 +97,38 @@ func ClaimTicket(ctx registry.CommandContext, ticket database.Ticket, userId uin
 		}
 	}
 
+	previousClaimer, err := dbclient.Client.TicketClaims.Get(ticket.GuildId, ticket.Id)
+	if err != nil {
+		return err
+	}
+
 	// Set to claimed in DB
 	if err := dbclient.Client.TicketClaims.Set(ticket.GuildId, ticket.Id, userId); err != nil {
 		return err
 	}
 
+	claimEvent := activity.Event{
+		Type:      redis.ActivityEventClaim,
+		GuildId:   ticket.GuildId,
+		TicketId:  ticket.Id,
+		ChannelId: *ticket.ChannelId,
+		ActorId:   actorId,
+		TargetId:  userId,
+	}
+
+	if previousClaimer != 0 && previousClaimer != userId {
+		claimEvent.Type = redis.ActivityEventTransfer
+		claimEvent.Previous = fmt.Sprintf("<@%d>", previousClaimer)
+	}
+
+	activity.Publish(ctx.Worker(), claimEvent)
+
+	go UpdateQueuePositions(ctx, ticket.GuildId)
+
+	if ticket.IsThread {
+		return claimThreadTicket(ctx, ticket, panel, userId)
+	}
+
 	newOverwrites, err := GenerateClaimedOverwrites(ctx.Worker(), ticket, userId)
 	if err != nil {
 		return err
@@ -72,6 +155,88 @@ func ClaimTicket(ctx registry.CommandContext, ticket database.Ticket, userId uin
 	return nil
 }
 
+// Threads have no per-user permission overwrites, so the closest equivalent to support being unable to view the ticket is
+// removing them from the thread. Support being unable to type cannot be enforced in a thread.
+func claimThreadTicket(ctx registry.CommandContext, ticket database.Ticket, panel *database.Panel, claimer uint64) error {
+	claimSettings, err := dbclient.Client.ClaimSettings.Get(ticket.GuildId)
+	if err != nil {
+		return err
+	}
+
+	if !claimSettings.SupportCanView {
+		if err := removeUnclaimedStaffFromThread(ctx.Worker(), ticket, claimer); err != nil {
+			return err
+		}
+	}
+
+	// The claimer may not have joined the thread yet
+	if err := ctx.Worker().AddThreadMember(*ticket.ChannelId, claimer); err != nil {
+		return err
+	}
+
+	return UpdateJoinThreadMessage(ctx.Worker(), ticket, panel, claimer, ctx.PremiumTier())
+}
+
+func removeUnclaimedStaffFromThread(worker *worker.Context, ticket database.Ticket, claimer uint64) error {
+	threadMembers, err := worker.ListThreadMembers(*ticket.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	// Users added with /add should stay in the thread
+	ticketMembers, err := dbclient.Client.TicketMembers.Get(ticket.GuildId, ticket.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, threadMember := range threadMembers {
+		userId := threadMember.UserId
+		if userId == claimer || userId == worker.BotId || utils.Contains(ticketMembers, userId) {
+			continue
+		}
+
+		// The claim has already been stored, so only the opener, admins and the claimer have permission
+		hasPermission, err := HasPermissionForTicket(worker, ticket, userId)
+		if err != nil || hasPermission {
+			continue
+		}
+
+		if err := worker.RemoveThreadMember(*ticket.ChannelId, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateJoinThreadMessage re-renders the join message in the ticket notification channel, if it has one
+func UpdateJoinThreadMessage(worker *worker.Context, ticket database.Ticket, panel *database.Panel, claimedBy uint64, premiumTier premium.PremiumTier) error {
+	if ticket.JoinMessageId == nil || ticket.ChannelId == nil {
+		return nil
+	}
+
+	settings, err := dbclient.Client.Settings.Get(ticket.GuildId)
+	if err != nil {
+		return err
+	}
+
+	if settings.TicketNotificationChannel == nil {
+		return nil
+	}
+
+	threadStaff, err := GetStaffInThread(worker, ticket, *ticket.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	data := BuildJoinThreadMessage(worker, ticket.GuildId, ticket.UserId, ticket.Id, panel, threadStaff, claimedBy, premiumTier)
+	if _, err := worker.EditMessage(*settings.TicketNotificationChannel, *ticket.JoinMessageId, data.IntoEditMessageData()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GenerateClaimedOverwrites If support reps can still view and type, returns (nil, nil)
 func GenerateClaimedOverwrites(worker *worker.Context, ticket database.Ticket, claimer uint64, otherUsers ...uint64) ([]channel.PermissionOverwrite, error) {
 	// Get claim settings for guild
@@ -64,52 +64,80 @@ func CloseTicket(ctx registry.CommandContext, reason *string, bypassPermissionCh
 		return
 	}
 
+	htmlSettings, err := redis.GetHtmlTranscriptSettings(ctx.GuildId())
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+	}
+
 	// Archive
-	if settings.StoreTranscripts {
-		msgs := make([]message.Message, 0)
+	var htmlTranscript []byte
+	var redactions int
+	if settings.StoreTranscripts || htmlSettings.Enabled() {
+		// The log of messages captured as they were sent also includes edits and deleted messages. The channel history
+		// is only paged through for tickets without a complete log.
+		msgs, ok, err := BuildTranscriptFromLog(ctx.GuildId(), ticket.Id, ctx.Worker().BotId)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorContext)
+		}
 
-		lastId := uint64(0)
-		count := -1
-		for count != 0 {
-			array, err := ctx.Worker().GetChannelMessages(ctx.ChannelId(), rest.GetChannelMessagesData{
-				Before: lastId,
-				Limit:  100,
-			})
+		if !ok {
+			msgs = fetchTranscriptMessages(ctx)
+		}
 
-			count = len(array)
+		// Personal information must be removed before the transcript is rendered or stored
+		redactions, err = RedactTranscript(ctx.GuildId(), msgs)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorContext)
+		}
+
+		if htmlSettings.Enabled() {
+			htmlTranscript, err = renderHtmlTranscript(ctx, ticket, msgs)
 			if err != nil {
-				count = 0
 				sentry.ErrorWithContext(err, errorContext)
+			}
+		}
+
+		if settings.StoreTranscripts {
+			isPremium := ctx.PremiumTier() > premium.None
+
+			// Attachments are preserved in the background, after which the transcript is uploaded again to link to the
+			// copies. Until then, and in the HTML transcript, attachments link to the Discord CDN.
+			var attachmentLimit int
+			if hasAttachments(msgs) {
+				attachmentLimit = attachmentPreservationLimit(ctx.PremiumTier())
+			}
 
-				// First rest interaction, check for 403
-				if err, ok := err.(request.RestError); ok && err.StatusCode == 403 {
-					if err := dbclient.Client.AutoCloseExclude.ExcludeAll(ctx.GuildId()); err != nil {
+			err = utils.TranscriptStore.Store(msgs, ctx.GuildId(), ticket.Id, isPremium)
+			if err == nil {
+				if err := dbclient.Client.Tickets.SetHasTranscript(ctx.GuildId(), ticket.Id, true); err != nil {
+					sentry.ErrorWithContext(err, errorContext)
+				}
+
+				if attachmentLimit > 0 {
+					if err := QueueAttachmentPreservation(ctx.GuildId(), ticket.Id, isPremium, attachmentLimit, msgs); err != nil {
 						sentry.ErrorWithContext(err, errorContext)
 					}
 				}
+			} else {
+				sentry.ErrorWithContext(err, errorContext)
 
-				break
-			}
-
-			if count > 0 {
-				lastId = array[len(array)-1].Id
-				msgs = append(msgs, array...)
+				// The channel is about to be deleted, so keep a copy of the messages to retry the upload with
+				if err := QueueTranscriptUpload(ctx.GuildId(), ticket.Id, isPremium, attachmentLimit, msgs, err); err != nil {
+					sentry.ErrorWithContext(err, errorContext)
+				}
 			}
 		}
+	}
 
-		// Reverse messages
-		for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
-			msgs[i], msgs[j] = msgs[j], msgs[i]
-		}
+	// The outbox keeps its own copy of the messages, so the log is never needed after the ticket is closed
+	if err := redis.DeleteTranscriptLog(ctx.GuildId(), ticket.Id); err != nil {
+		sentry.ErrorWithContext(err, errorContext)
+	}
 
-		err := utils.ArchiverClient.Store(msgs, ctx.GuildId(), ticket.Id, ctx.PremiumTier() > premium.None)
-		if err == nil {
-			if err := dbclient.Client.Tickets.SetHasTranscript(ctx.GuildId(), ticket.Id, true); err != nil {
-				sentry.ErrorWithContext(err, errorContext)
-			}
-		} else {
-			sentry.ErrorWithContext(err, errorContext)
-		}
+	// Threads are deleted along with the ticket channel, so notes must be collected first
+	staffNotes, err := collectStaffNotes(ctx, ticket)
+	if err != nil {
+		sentry.ErrorWithContext(err, errorContext)
 	}
 
 	// Set ticket state as closed and delete channel
@@ -120,6 +148,8 @@ func CloseTicket(ctx registry.CommandContext, reason *string, bypassPermissionCh
 
 	success = true
 
+	go UpdateQueuePositions(ctx, ticket.GuildId)
+
 	// set close reason
 	if reason != nil {
 		if err := dbclient.Client.CloseReason.Set(ctx.GuildId(), ticket.Id, *reason); err != nil {
@@ -159,16 +189,23 @@ func CloseTicket(ctx registry.CommandContext, reason *string, bypassPermissionCh
 			return
 		}
 	} else {
-		if _, err := ctx.Worker().DeleteChannel(ctx.ChannelId()); err != nil {
-			// Check if we should exclude this from autoclose
-			if restError, ok := err.(request.RestError); ok && restError.StatusCode == 403 {
-				if err := dbclient.Client.AutoCloseExclude.Exclude(ticket.GuildId, ticket.Id); err != nil {
-					sentry.ErrorWithContext(err, errorContext)
+		archived, err := ArchiveTicketChannel(ctx, ticket)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorContext)
+		}
+
+		if !archived {
+			if _, err := ctx.Worker().DeleteChannel(ctx.ChannelId()); err != nil {
+				// Check if we should exclude this from autoclose
+				if restError, ok := err.(request.RestError); ok && restError.StatusCode == 403 {
+					if err := dbclient.Client.AutoCloseExclude.Exclude(ticket.GuildId, ticket.Id); err != nil {
+						sentry.ErrorWithContext(err, errorContext)
+					}
 				}
-			}
 
-			ctx.HandleError(err)
-			return
+				ctx.HandleError(err)
+				return
+			}
 		}
 	}
 
@@ -189,10 +226,10 @@ func CloseTicket(ctx registry.CommandContext, reason *string, bypassPermissionCh
 		}
 	}
 
-	sendCloseEmbed(ctx, errorContext, member, settings, ticket, reason)
+	sendCloseEmbed(ctx, errorContext, member, settings, htmlSettings, ticket, reason, staffNotes, htmlTranscript, redactions)
 }
 
-func sendCloseEmbed(ctx registry.CommandContext, errorContext sentry.ErrorContext, member member.Member, settings database.Settings, ticket database.Ticket, reason *string) {
+func sendCloseEmbed(ctx registry.CommandContext, errorContext sentry.ErrorContext, member member.Member, settings database.Settings, htmlSettings redis.HtmlTranscriptSettings, ticket database.Ticket, reason *string, staffNotes []message.Message, htmlTranscript []byte, redactions int) {
 	// Send logs to archive channel
 	archiveChannelId, err := dbclient.Client.ArchiveChannel.Get(ticket.GuildId)
 	if err != nil {
@@ -206,22 +243,52 @@ func sendCloseEmbed(ctx registry.CommandContext, errorContext sentry.ErrorContex
 		}
 	}
 
-	closeEmbed, closeComponents := buildCloseEmbed(ctx, ticket, settings, member, reason)
+	closeEmbed := buildCloseEmbed(ctx, ticket, member, reason, redactions)
+
+	// The archive channel keeps the message permanently, so links to the dashboard rather than an expiring link
+	closeComponents := buildCloseComponents(ctx, ticket, settings, TranscriptUrl(ticket.GuildId, ticket.Id))
 
 	if archiveChannelExists && archiveChannelId != nil {
+		// Staff notes are only included in the archive channel, as the opener is sent the same embed
+		staffNotesFile := buildStaffNotesFile(ticket, staffNotes)
+
 		data := rest.CreateMessageData{
 			Embeds:     utils.Slice(closeEmbed),
 			Components: closeComponents,
 		}
 
-		if _, err := ctx.Worker().CreateMessageComplex(*archiveChannelId, data); err != nil {
+		if htmlSettings.ArchiveChannel {
+			data.File = buildHtmlTranscriptFile(ticket, htmlTranscript)
+		}
+
+		// Only one file can be attached to each message
+		if data.File == nil {
+			data.File = staffNotesFile
+			staffNotesFile = nil
+		}
+
+		msg, err := ctx.Worker().CreateMessageComplex(*archiveChannelId, data)
+		if err != nil {
 			sentry.ErrorWithContext(err, errorContext)
+		} else if staffNotesFile != nil {
+			notesData := rest.CreateMessageData{
+				File: staffNotesFile,
+				MessageReference: &message.MessageReference{
+					MessageId: msg.Id,
+					ChannelId: *archiveChannelId,
+					GuildId:   ticket.GuildId,
+				},
+			}
+
+			if _, err := ctx.Worker().CreateMessageComplex(*archiveChannelId, notesData); err != nil {
+				sentry.ErrorWithContext(err, errorContext)
+			}
 		}
 	}
 
 	// Notify user and send logs in DMs
 	// This mutates state!
-	dmChannel, ok := getDmChannel(ctx, ticket.UserId)
+	dmChannel, ok := GetDmChannel(ctx, ticket.UserId)
 	if ok {
 		guild, err := ctx.Guild()
 		if err != nil {
@@ -229,6 +296,9 @@ func sendCloseEmbed(ctx registry.CommandContext, errorContext sentry.ErrorContex
 			return
 		}
 
+		// The opener may not be able to log in to the dashboard
+		closeComponents = buildCloseComponents(ctx, ticket, settings, SignedTranscriptUrl(ctx.Worker(), ticket.GuildId, ticket.Id))
+
 		closeEmbed.SetAuthor(guild.Name, "", fmt.Sprintf("https://cdn.discordapp.com/icons/%d/%s.png", guild.Id, guild.Icon))
 
 		feedbackEnabled, err := dbclient.Client.FeedbackEnabled.Get(ctx.GuildId())
@@ -256,13 +326,17 @@ func sendCloseEmbed(ctx registry.CommandContext, errorContext sentry.ErrorContex
 			Components: closeComponents,
 		}
 
+		if htmlSettings.DirectMessage {
+			data.File = buildHtmlTranscriptFile(ticket, htmlTranscript)
+		}
+
 		if _, err := ctx.Worker().CreateMessageComplex(dmChannel, data); err != nil {
 			sentry.ErrorWithContext(err, errorContext)
 		}
 	}
 }
 
-func buildCloseEmbed(ctx registry.CommandContext, ticket database.Ticket, settings database.Settings, member member.Member, reason *string) (*embed.Embed, []component.Component) {
+func buildCloseEmbed(ctx registry.CommandContext, ticket database.Ticket, member member.Member, reason *string, redactions int) *embed.Embed {
 	var formattedReason string
 	if reason == nil {
 		formattedReason = "No reason specified"
@@ -300,7 +374,23 @@ func buildCloseEmbed(ctx registry.CommandContext, ticket database.Ticket, settin
 		AddBlankField(true).
 		AddField(formatTitle("Reason", customisation.EmojiReason, ctx.Worker().IsWhitelabel), formattedReason, false)
 
-	// Build action row
+	labels, err := redis.GetTicketLabels(ticket.GuildId, ticket.Id)
+	if err != nil {
+		sentry.Error(err)
+	}
+
+	if len(labels) > 0 {
+		closeEmbed.AddField(formatTitle("Labels", customisation.EmojiSubject, ctx.Worker().IsWhitelabel), FormatLabels(labels), false)
+	}
+
+	if redactions > 0 {
+		closeEmbed.AddField(formatTitle("Redactions", customisation.EmojiReason, ctx.Worker().IsWhitelabel), fmt.Sprintf("%d values were removed from the transcript", redactions), false)
+	}
+
+	return closeEmbed
+}
+
+func buildCloseComponents(ctx registry.CommandContext, ticket database.Ticket, settings database.Settings, transcriptLink string) []component.Component {
 	var transcriptEmoji *emoji.Emoji
 	if !ctx.Worker().IsWhitelabel {
 		transcriptEmoji = customisation.EmojiTranscript.BuildEmoji()
@@ -313,8 +403,6 @@ func buildCloseEmbed(ctx registry.CommandContext, ticket database.Ticket, settin
 
 	var transcriptButtons []component.Component
 	if settings.StoreTranscripts {
-		transcriptLink := fmt.Sprintf("https://panel.ticketsbot.net/manage/%d/transcripts/view/%d", ticket.GuildId, ticket.Id)
-
 		transcriptButtons = append(transcriptButtons, component.BuildButton(component.Button{
 			Label: "View Online Transcript",
 			Style: component.ButtonStyleLink,
@@ -335,9 +423,9 @@ func buildCloseEmbed(ctx registry.CommandContext, ticket database.Ticket, settin
 	}
 
 	if len(transcriptButtons) == 0 {
-		return closeEmbed, nil
+		return nil
 	} else {
-		return closeEmbed, []component.Component{
+		return []component.Component{
 			component.BuildActionRow(transcriptButtons...),
 		}
 	}
@@ -351,7 +439,7 @@ func formatTitle(s string, emoji customisation.CustomEmoji, isWhitelabel bool) s
 	}
 }
 
-func getDmChannel(ctx registry.CommandContext, userId uint64) (uint64, bool) {
+func GetDmChannel(ctx registry.CommandContext, userId uint64) (uint64, bool) {
 	// Hack for autoclose
 	if ctx.Worker().BotId == userId {
 		return 0, false
@@ -417,3 +505,44 @@ func buildRatingActionRow(ticket database.Ticket) component.Component {
 
 	return component.BuildActionRow(buttons...)
 }
+
+// Retrieves the messages in the ticket channel from Discord, in the order they were sent
+func fetchTranscriptMessages(ctx registry.CommandContext) []message.Message {
+	msgs := make([]message.Message, 0)
+
+	lastId := uint64(0)
+	count := -1
+	for count != 0 {
+		array, err := ctx.Worker().GetChannelMessages(ctx.ChannelId(), rest.GetChannelMessagesData{
+			Before: lastId,
+			Limit:  100,
+		})
+
+		count = len(array)
+		if err != nil {
+			count = 0
+			sentry.ErrorWithContext(err, ctx.ToErrorContext())
+
+			// First rest interaction, check for 403
+			if err, ok := err.(request.RestError); ok && err.StatusCode == 403 {
+				if err := dbclient.Client.AutoCloseExclude.ExcludeAll(ctx.GuildId()); err != nil {
+					sentry.ErrorWithContext(err, ctx.ToErrorContext())
+				}
+			}
+
+			break
+		}
+
+		if count > 0 {
+			lastId = array[len(array)-1].Id
+			msgs = append(msgs, array...)
+		}
+	}
+
+	// Reverse messages
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+
+	return msgs
+}
@@ -0,0 +1,85 @@
+package logic
+
+import (
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"time"
+)
+
+// FindBulkCloseTickets returns the open tickets in the guild that match the filter
+func FindBulkCloseTickets(guildId uint64, filter redis.BulkCloseFilter) ([]database.Ticket, error) {
+	options := database.TicketQueryOptions{
+		GuildId: guildId,
+		Open:    utils.Ptr(true),
+		Order:   database.OrderTypeAscending,
+	}
+
+	if filter.PanelId != nil {
+		options.PanelId = *filter.PanelId
+	}
+
+	tickets, err := dbclient.Client.Tickets.GetByOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Loaded once, rather than per ticket
+	var claimers map[int]uint64
+	if filter.Unclaimed || filter.ClaimedBy != nil {
+		claimers, err = dbclient.GetOpenTicketClaimers(guildId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var matched []database.Ticket
+	for _, ticket := range tickets {
+		ok, err := matchesBulkCloseFilter(ticket, filter, claimers)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			matched = append(matched, ticket)
+		}
+	}
+
+	return matched, nil
+}
+
+// claimers maps ticket IDs to the member that claimed them, and is only required if the filter checks claims
+func matchesBulkCloseFilter(ticket database.Ticket, filter redis.BulkCloseFilter, claimers map[int]uint64) (bool, error) {
+	if filter.OpenedBefore != nil && !ticket.OpenTime.Before(*filter.OpenedBefore) {
+		return false, nil
+	}
+
+	claimedBy := claimers[ticket.Id]
+	if filter.Unclaimed && claimedBy != 0 {
+		return false, nil
+	}
+
+	if filter.ClaimedBy != nil && claimedBy != *filter.ClaimedBy {
+		return false, nil
+	}
+
+	if filter.InactiveHours != nil {
+		lastMessage, err := dbclient.Client.TicketLastMessage.Get(ticket.GuildId, ticket.Id)
+		if err != nil {
+			return false, err
+		}
+
+		// If no messages have been sent, the ticket has been inactive since it was opened
+		lastActive := ticket.OpenTime
+		if lastMessage.LastMessageTime != nil {
+			lastActive = *lastMessage.LastMessageTime
+		}
+
+		if time.Since(lastActive) < time.Duration(*filter.InactiveHours)*time.Hour {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
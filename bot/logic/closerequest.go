@@ -0,0 +1,37 @@
+package logic
+
+import (
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/activity"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/objects/channel/message"
+)
+
+// RequestClose stores the close request, replacing any existing one for the ticket
+func RequestClose(ctx registry.CommandContext, ticket database.Ticket, closeRequest database.CloseRequest) error {
+	if err := dbclient.Client.CloseRequest.Set(closeRequest); err != nil {
+		return err
+	}
+
+	event := activity.Event{
+		Type:     redis.ActivityEventCloseRequest,
+		GuildId:  ticket.GuildId,
+		TicketId: ticket.Id,
+		ActorId:  closeRequest.UserId,
+		TargetId: ticket.UserId,
+		Reason:   closeRequest.Reason,
+	}
+
+	if ticket.ChannelId != nil {
+		event.ChannelId = *ticket.ChannelId
+	}
+
+	if closeRequest.CloseAt != nil {
+		event.Current = message.BuildTimestamp(*closeRequest.CloseAt, message.TimestampStyleRelativeTime)
+	}
+
+	activity.Publish(ctx.Worker(), event)
+	return nil
+}
@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/integrations/bloxlink"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/integrations"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"strings"
+	"time"
+)
+
+// CheckPanelEligibility returns whether the user meets the panel's requirements, replying with everything they are
+// missing if not
+func CheckPanelEligibility(ctx registry.CommandContext, panel database.Panel) (bool, error) {
+	requirements, ok, err := redis.GetPanelRequirements(panel.GuildId, panel.PanelId)
+	if err != nil || !ok {
+		return true, err
+	}
+
+	member, err := ctx.Member()
+	if err != nil {
+		return false, err
+	}
+
+	var failures []string
+
+	if len(requirements.RequiredRoles) > 0 && !utils.HasIntersection(requirements.RequiredRoles, member.Roles) {
+		failures = append(failures, ctx.GetMessage(i18n.MessageEligibilityRequiredRole, formatRoleMentions(requirements.RequiredRoles)))
+	}
+
+	if utils.HasIntersection(requirements.ForbiddenRoles, member.Roles) {
+		failures = append(failures, ctx.GetMessage(i18n.MessageEligibilityForbiddenRole, formatRoleMentions(requirements.ForbiddenRoles)))
+	}
+
+	if requirements.MinAccountAgeDays > 0 {
+		createdAt := utils.SnowflakeToTime(ctx.UserId())
+		if time.Since(createdAt) < time.Duration(requirements.MinAccountAgeDays)*time.Hour*24 {
+			failures = append(failures, ctx.GetMessage(i18n.MessageEligibilityAccountAge, requirements.MinAccountAgeDays))
+		}
+	}
+
+	if requirements.MinMemberDays > 0 {
+		if time.Since(member.JoinedAt) < time.Duration(requirements.MinMemberDays)*time.Hour*24 {
+			failures = append(failures, ctx.GetMessage(i18n.MessageEligibilityMemberAge, requirements.MinMemberDays))
+		}
+	}
+
+	if requirements.RequireBloxlink {
+		if _, err := integrations.Bloxlink.GetRobloxUser(ctx.UserId()); err != nil {
+			if err != bloxlink.ErrUserNotFound {
+				return false, err
+			}
+
+			failures = append(failures, ctx.GetMessage(i18n.MessageEligibilityBloxlink))
+		}
+	}
+
+	if len(failures) == 0 {
+		return true, nil
+	}
+
+	for i, failure := range failures {
+		failures[i] = fmt.Sprintf("• %s", failure)
+	}
+
+	ctx.ReplyRaw(customisation.Red, ctx.GetMessage(i18n.TitleNotEligible), strings.Join(failures, "\n"))
+	return false, nil
+}
+
+func formatRoleMentions(roleIds []uint64) string {
+	mentions := make([]string, len(roleIds))
+	for i, roleId := range roleIds {
+		mentions[i] = fmt.Sprintf("<@&%d>", roleId)
+	}
+
+	return strings.Join(mentions, ", ")
+}
@@ -0,0 +1,117 @@
+package logic
+
+import (
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/interaction/component"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	FormShortMaxLength     = 255
+	FormParagraphMaxLength = 1024 // Max embed field value
+)
+
+var (
+	emailPattern     = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	discordIdPattern = regexp.MustCompile(`^\d{17,20}$`)
+)
+
+// FormInputMaxLength returns the most characters an answer can be, which is limited by where answers are displayed
+func FormInputMaxLength(input database.FormInput) int {
+	if component.TextStyleTypes(input.Style) == component.TextStyleParagraph {
+		return FormParagraphMaxLength
+	} else {
+		return FormShortMaxLength
+	}
+}
+
+// ValidateFormAnswers returns a message for each answer that is blank when required, or breaks its validation rules
+func ValidateFormAnswers(ctx registry.CommandContext, answers map[database.FormInput]string, validation map[int]redis.FormInputValidation) []string {
+	var failures []string
+	for input, answer := range answers {
+		// Check that users have not just pressed newline or space
+		if strings.TrimSpace(answer) == "" {
+			if input.Required {
+				failures = append(failures, ctx.GetMessage(i18n.MessageFormMissingInput, input.Label))
+			}
+
+			continue
+		}
+
+		rules, ok := validation[input.Id]
+		if !ok {
+			continue
+		}
+
+		if failure, ok := validateFormAnswer(ctx, input, rules, answer); !ok {
+			failures = append(failures, failure)
+		}
+	}
+
+	return failures
+}
+
+func validateFormAnswer(ctx registry.CommandContext, input database.FormInput, rules redis.FormInputValidation, answer string) (string, bool) {
+	length := utf8.RuneCountInString(answer)
+	if rules.MinLength != nil && length < *rules.MinLength {
+		return ctx.GetMessage(i18n.MessageFormValidationTooShort, input.Label, *rules.MinLength), false
+	}
+
+	if rules.MaxLength != nil && length > *rules.MaxLength {
+		return ctx.GetMessage(i18n.MessageFormValidationTooLong, input.Label, *rules.MaxLength), false
+	}
+
+	switch rules.Format {
+	case redis.FormInputFormatNumber:
+		number, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+		if err != nil {
+			return ctx.GetMessage(i18n.MessageFormValidationNumber, input.Label), false
+		}
+
+		if rules.Min != nil && number < *rules.Min {
+			return ctx.GetMessage(i18n.MessageFormValidationNumberTooLow, input.Label, formatFloat(*rules.Min)), false
+		}
+
+		if rules.Max != nil && number > *rules.Max {
+			return ctx.GetMessage(i18n.MessageFormValidationNumberTooHigh, input.Label, formatFloat(*rules.Max)), false
+		}
+	case redis.FormInputFormatEmail:
+		if !emailPattern.MatchString(strings.TrimSpace(answer)) {
+			return ctx.GetMessage(i18n.MessageFormValidationEmail, input.Label), false
+		}
+	case redis.FormInputFormatUrl:
+		parsed, err := url.ParseRequestURI(strings.TrimSpace(answer))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return ctx.GetMessage(i18n.MessageFormValidationUrl, input.Label), false
+		}
+	case redis.FormInputFormatDiscordId:
+		if !discordIdPattern.MatchString(strings.TrimSpace(answer)) {
+			return ctx.GetMessage(i18n.MessageFormValidationDiscordId, input.Label), false
+		}
+
+		if _, err := strconv.ParseUint(strings.TrimSpace(answer), 10, 64); err != nil {
+			return ctx.GetMessage(i18n.MessageFormValidationDiscordId, input.Label), false
+		}
+	}
+
+	if rules.Pattern != nil {
+		// Patterns are validated when they are set
+		pattern, err := regexp.Compile(*rules.Pattern)
+		if err == nil && !pattern.MatchString(answer) {
+			return ctx.GetMessage(i18n.MessageFormValidationPattern, input.Label), false
+		}
+	}
+
+	return "", true
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
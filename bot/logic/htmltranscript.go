@@ -0,0 +1,70 @@
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/transcripts"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/rest"
+)
+
+// Renders the messages as a standalone HTML transcript, resolving mentions to the guild's current role and channel
+// names. Must be called before the ticket channel is deleted.
+func renderHtmlTranscript(ctx registry.CommandContext, ticket database.Ticket, messages []message.Message) ([]byte, error) {
+	guild, err := ctx.Guild()
+	if err != nil {
+		return nil, err
+	}
+
+	data := transcripts.HtmlTranscriptData{
+		GuildName: guild.Name,
+		TicketId:  ticket.Id,
+		Roles:     make(map[uint64]string),
+		Channels:  make(map[uint64]string),
+	}
+
+	if guild.Icon != "" {
+		data.GuildIcon = fmt.Sprintf("https://cdn.discordapp.com/icons/%d/%s.png", guild.Id, guild.Icon)
+	}
+
+	roles, err := ctx.Worker().GetGuildRoles(ticket.GuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		data.Roles[role.Id] = role.Name
+	}
+
+	channels, err := ctx.Worker().GetGuildChannels(ticket.GuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ch := range channels {
+		data.Channels[ch.Id] = ch.Name
+	}
+
+	if ticket.ChannelId != nil {
+		if ch, err := ctx.Worker().GetChannel(*ticket.ChannelId); err == nil {
+			data.ChannelName = ch.Name
+		}
+	}
+
+	return transcripts.RenderHtml(messages, data)
+}
+
+// Returns nil if no transcript was rendered. A new file must be built for each message, as the reader is consumed.
+func buildHtmlTranscriptFile(ticket database.Ticket, transcript []byte) *rest.File {
+	if transcript == nil {
+		return nil
+	}
+
+	return &rest.File{
+		Name:        fmt.Sprintf("transcript-%d.html", ticket.Id),
+		ContentType: "text/html",
+		Reader:      bytes.NewReader(transcript),
+	}
+}
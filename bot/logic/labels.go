@@ -0,0 +1,167 @@
+package logic
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/activity"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var labelWhitespacePattern = regexp.MustCompile(`\s+`)
+
+// NormaliseLabel converts a label to the form it is stored in, e.g. "Bug Report" becomes "bug-report". Returns false
+// if the label is empty or too long.
+func NormaliseLabel(label string) (string, bool) {
+	label = labelWhitespacePattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(label)), "-")
+	if len(label) == 0 || len(label) > redis.MaxLabelLength {
+		return "", false
+	}
+
+	return label, true
+}
+
+// ParseLabels parses a comma separated list of labels
+func ParseLabels(s string) ([]string, bool) {
+	var labels []string
+	for _, raw := range strings.Split(s, ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		label, ok := NormaliseLabel(raw)
+		if !ok {
+			return nil, false
+		}
+
+		labels = append(labels, label)
+	}
+
+	return labels, true
+}
+
+// AddTicketLabel applies the label to the ticket, creating it if the guild does not have it yet. Returns false if the
+// guild is at its label limit.
+func AddTicketLabel(ctx registry.CommandContext, ticket database.Ticket, label string) (bool, error) {
+	created, err := redis.CreateGuildLabels(ticket.GuildId, label)
+	if err != nil || !created {
+		return false, err
+	}
+
+	previous, err := redis.GetTicketLabels(ticket.GuildId, ticket.Id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := redis.AddTicketLabels(ticket.GuildId, ticket.Id, label); err != nil {
+		return false, err
+	}
+
+	publishLabelChange(ctx, ticket, ctx.UserId(), previous)
+	return true, nil
+}
+
+// RemoveTicketLabel returns false if the ticket did not have the label
+func RemoveTicketLabel(ctx registry.CommandContext, ticket database.Ticket, label string) (bool, error) {
+	previous, err := redis.GetTicketLabels(ticket.GuildId, ticket.Id)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := redis.RemoveTicketLabel(ticket.GuildId, ticket.Id, label)
+	if err != nil || !removed {
+		return false, err
+	}
+
+	publishLabelChange(ctx, ticket, ctx.UserId(), previous)
+	return true, nil
+}
+
+// Applies the panel's default labels to a newly opened ticket
+func applyPanelLabels(ctx registry.CommandContext, ticket database.Ticket, panel *database.Panel) error {
+	if panel == nil {
+		return nil
+	}
+
+	labels, err := redis.GetPanelLabels(ticket.GuildId, panel.PanelId)
+	if err != nil {
+		return err
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+
+	// Don't recreate labels that have since been deleted from the guild
+	existing, err := redis.GetGuildLabels(ticket.GuildId)
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if utils.Contains(existing, label) {
+			filtered = append(filtered, label)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return nil
+	}
+
+	if err := redis.AddTicketLabels(ticket.GuildId, ticket.Id, filtered...); err != nil {
+		return err
+	}
+
+	publishLabelChange(ctx, ticket, ctx.Worker().BotId, nil)
+	return nil
+}
+
+// Logs the labels of the ticket before and after a change to the activity log
+func publishLabelChange(ctx registry.CommandContext, ticket database.Ticket, actorId uint64, previous []string) {
+	current, err := redis.GetTicketLabels(ticket.GuildId, ticket.Id)
+	if err != nil {
+		sentry.ErrorWithContext(err, ctx.ToErrorContext())
+		return
+	}
+
+	event := activity.Event{
+		Type:     redis.ActivityEventLabel,
+		GuildId:  ticket.GuildId,
+		TicketId: ticket.Id,
+		ActorId:  actorId,
+		Previous: "None",
+		Current:  "None",
+	}
+
+	if ticket.ChannelId != nil {
+		event.ChannelId = *ticket.ChannelId
+	}
+
+	if len(previous) > 0 {
+		event.Previous = FormatLabels(previous)
+	}
+
+	if len(current) > 0 {
+		event.Current = FormatLabels(current)
+	}
+
+	activity.Publish(ctx.Worker(), event)
+}
+
+// FormatLabels returns the labels as a sorted list of inline code blocks
+func FormatLabels(labels []string) string {
+	sort.Strings(labels)
+
+	formatted := make([]string, len(labels))
+	for i, label := range labels {
+		formatted[i] = fmt.Sprintf("`%s`", label)
+	}
+
+	return strings.Join(formatted, " ")
+}
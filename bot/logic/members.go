@@ -0,0 +1,85 @@
+package logic
+
+import (
+	"errors"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/activity"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/permission"
+)
+
+// AddTicketMember gives the user access to the ticket. For thread tickets, the REST error is returned as is, so that
+// callers can tell the user when the member cannot see the parent channel.
+func AddTicketMember(ctx registry.CommandContext, ticket database.Ticket, userId uint64) error {
+	if ticket.ChannelId == nil {
+		return errors.New("channel ID is nil")
+	}
+
+	if err := dbclient.Client.TicketMembers.Add(ticket.GuildId, ticket.Id, userId); err != nil {
+		return err
+	}
+
+	if ticket.IsThread {
+		if err := ctx.Worker().AddThreadMember(*ticket.ChannelId, userId); err != nil {
+			return err
+		}
+	} else {
+		additionalPermissions, err := dbclient.Client.TicketPermissions.Get(ticket.GuildId)
+		if err != nil {
+			return err
+		}
+
+		data := BuildUserOverwrite(userId, additionalPermissions)
+		if err := ctx.Worker().EditChannelPermissions(*ticket.ChannelId, data); err != nil {
+			return err
+		}
+	}
+
+	publishMemberChange(ctx, ticket, redis.ActivityEventMemberAdd, userId)
+	return nil
+}
+
+// RemoveTicketMember removes the user's access to the ticket
+func RemoveTicketMember(ctx registry.CommandContext, ticket database.Ticket, userId uint64) error {
+	if ticket.ChannelId == nil {
+		return errors.New("channel ID is nil")
+	}
+
+	if err := dbclient.Client.TicketMembers.Delete(ticket.GuildId, ticket.Id, userId); err != nil {
+		return err
+	}
+
+	if ticket.IsThread {
+		if err := ctx.Worker().RemoveThreadMember(*ticket.ChannelId, userId); err != nil {
+			return err
+		}
+	} else {
+		data := channel.PermissionOverwrite{
+			Id:    userId,
+			Type:  channel.PermissionTypeMember,
+			Allow: 0,
+			Deny:  permission.BuildPermissions(StandardPermissions[:]...),
+		}
+
+		if err := ctx.Worker().EditChannelPermissions(*ticket.ChannelId, data); err != nil {
+			return err
+		}
+	}
+
+	publishMemberChange(ctx, ticket, redis.ActivityEventMemberRemove, userId)
+	return nil
+}
+
+func publishMemberChange(ctx registry.CommandContext, ticket database.Ticket, eventType redis.ActivityEvent, userId uint64) {
+	activity.Publish(ctx.Worker(), activity.Event{
+		Type:      eventType,
+		GuildId:   ticket.GuildId,
+		TicketId:  ticket.Id,
+		ChannelId: *ticket.ChannelId,
+		ActorId:   ctx.UserId(),
+		TargetId:  userId,
+	})
+}
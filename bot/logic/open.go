@@ -3,11 +3,11 @@ package logic
 import (
 	"context"
 	"fmt"
-	permcache "github.com/TicketsBot/common/permission"
 	"github.com/TicketsBot/common/premium"
 	"github.com/TicketsBot/common/sentry"
 	"github.com/TicketsBot/database"
 	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/activity"
 	"github.com/TicketsBot/worker/bot/command"
 	"github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
@@ -20,6 +20,7 @@ import (
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/objects/channel/embed"
 	"github.com/rxdn/gdl/objects/channel/message"
 	"github.com/rxdn/gdl/objects/interaction/component"
 	"github.com/rxdn/gdl/objects/member"
@@ -36,19 +37,102 @@ import (
 func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject string, formData map[database.FormInput]string) (database.Ticket, error) {
 	// Make sure ticket count is within ticket limit
 	// Check ticket limit before ratelimit token to prevent 1 person from stopping everyone opening tickets
-	violatesTicketLimit, limit := getTicketLimit(ctx)
-	if violatesTicketLimit {
-		// Notify the user
-		ticketsPluralised := "ticket"
-		if limit > 1 {
-			ticketsPluralised += "s"
-		}
+	withinLimits, err := CheckTicketLimits(ctx, panel)
+	if err != nil {
+		ctx.HandleError(err)
+		return database.Ticket{}, err
+	}
 
-		// TODO: Use translation of tickets
-		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTicketLimitReached, limit, ticketsPluralised)
+	if !withinLimits {
 		return database.Ticket{}, fmt.Errorf("ticket limit reached")
 	}
 
+	canOpen, businessHoursNotice, err := CheckBusinessHours(ctx, panel)
+	if err != nil {
+		ctx.HandleError(err)
+		return database.Ticket{}, err
+	}
+
+	if !canOpen {
+		return database.Ticket{}, nil
+	}
+
+	var additionalEmbeds []*embed.Embed
+	if businessHoursNotice != nil {
+		additionalEmbeds = append(additionalEmbeds, businessHoursNotice)
+	}
+
+	return openTicket(ctx, panel, subject, formData, ctx.UserId(), additionalEmbeds)
+}
+
+// OpenTicketOnBehalf opens a ticket as a staff member, with another user as the opener. Ticket limits and business hours
+// only apply to users opening their own tickets, so are skipped. The staff member is added to the ticket, and the opener
+// is notified by DM. An audit log entry is always written, as the opener's limits were bypassed.
+func OpenTicketOnBehalf(ctx registry.CommandContext, panel *database.Panel, subject string, openerId uint64) (database.Ticket, error) {
+	ticket, err := openTicket(ctx, panel, subject, nil, openerId, nil)
+	if err != nil {
+		return ticket, err
+	}
+
+	entry := redis.AuditLogEntry{
+		Action:   redis.AuditActionOpenOnBehalf,
+		ActorId:  ctx.UserId(),
+		TargetId: openerId,
+		TicketId: ticket.Id,
+		Time:     time.Now(),
+	}
+
+	if err := redis.AddAuditLogEntry(ticket.GuildId, entry); err != nil {
+		ctx.HandleError(err)
+	}
+
+	if ticket.ChannelId == nil {
+		return ticket, nil
+	}
+
+	if err := addStaffToTicket(ctx, ticket); err != nil {
+		ctx.HandleError(err)
+	}
+
+	notice := utils.BuildEmbed(ctx, customisation.Green, i18n.Ticket, i18n.MessageOpenedOnBehalf, nil, ctx.UserId(), openerId)
+	if _, err := ctx.Worker().CreateMessageEmbed(*ticket.ChannelId, notice); err != nil {
+		ctx.HandleError(err)
+	}
+
+	if dmChannel, ok := GetDmChannel(ctx, openerId); ok {
+		guild, err := ctx.Guild()
+		if err != nil {
+			ctx.HandleError(err)
+		} else {
+			dm := utils.BuildEmbed(ctx, customisation.Green, i18n.Ticket, i18n.MessageOpenedOnBehalfDM, nil, guild.Name, *ticket.ChannelId)
+			if _, err := ctx.Worker().CreateMessageEmbed(dmChannel, dm); err != nil {
+				// The user likely has DMs disabled
+				sentry.LogWithContext(err, ctx.ToErrorContext())
+			}
+		}
+	}
+
+	return ticket, nil
+}
+
+func addStaffToTicket(ctx registry.CommandContext, ticket database.Ticket) error {
+	if err := dbclient.Client.TicketMembers.Add(ticket.GuildId, ticket.Id, ctx.UserId()); err != nil {
+		return err
+	}
+
+	if ticket.IsThread {
+		return ctx.Worker().AddThreadMember(*ticket.ChannelId, ctx.UserId())
+	}
+
+	additionalPermissions, err := dbclient.Client.TicketPermissions.Get(ticket.GuildId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Worker().EditChannelPermissions(*ticket.ChannelId, BuildUserOverwrite(ctx.UserId(), additionalPermissions))
+}
+
+func openTicket(ctx registry.CommandContext, panel *database.Panel, subject string, formData map[database.FormInput]string, openerId uint64, additionalEmbeds []*embed.Embed) (database.Ticket, error) {
 	ok, err := redis.TakeTicketRateLimitToken(redis.Client, ctx.GuildId())
 	if err != nil {
 		ctx.HandleError(err)
@@ -176,13 +260,37 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 	}
 
 	// Create channel
-	ticketId, err := dbclient.Client.Tickets.Create(ctx.GuildId(), ctx.UserId(), isThread)
+	ticketId, err := dbclient.Client.Tickets.Create(ctx.GuildId(), openerId, isThread)
 	if err != nil {
 		ctx.HandleError(err)
 		return database.Ticket{}, err
 	}
 
-	name, err := GenerateChannelName(ctx, panel, ticketId, ctx.UserId(), nil)
+	// Messages are logged as they are sent, so the log must exist before the channel does. The log is also where the
+	// message log channel gets the previous content of edited and deleted messages from. Guilds that use neither do not
+	// have their messages logged at all.
+	htmlSettings, err := redis.GetHtmlTranscriptSettings(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+	}
+
+	messageLogChannel, err := redis.GetMessageLogChannel(ctx.GuildId())
+	if err != nil {
+		ctx.HandleError(err)
+	}
+
+	if settings.StoreTranscripts || htmlSettings.Enabled() || messageLogChannel != nil {
+		if err := redis.StartTranscriptLog(ctx.GuildId(), ticketId); err != nil {
+			ctx.HandleError(err)
+		}
+	}
+
+	// Form answers can be used in the naming scheme, so must be stored first
+	if err := redis.SetTicketFormAnswers(ctx.GuildId(), ticketId, getFormAnswerPlaceholders(formData)); err != nil {
+		ctx.HandleError(err)
+	}
+
+	name, err := GenerateChannelName(ctx, panel, ticketId, openerId, nil)
 	if err != nil {
 		ctx.HandleError(err)
 		return database.Ticket{}, err
@@ -204,12 +312,12 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 		}
 
 		// Join ticket
-		if err := ctx.Worker().AddThreadMember(ch.Id, ctx.UserId()); err != nil {
+		if err := ctx.Worker().AddThreadMember(ch.Id, openerId); err != nil {
 			ctx.HandleError(err)
 		}
 
 		if settings.TicketNotificationChannel != nil {
-			data := BuildJoinThreadMessage(ctx.Worker(), ctx.GuildId(), ctx.UserId(), ticketId, panel, nil, ctx.PremiumTier())
+			data := BuildJoinThreadMessage(ctx.Worker(), ctx.GuildId(), openerId, ticketId, panel, nil, 0, ctx.PremiumTier())
 
 			if msg, err := ctx.Worker().CreateMessageComplex(*settings.TicketNotificationChannel, data.IntoCreateMessageData()); err == nil {
 				joinMessageId = &msg.Id
@@ -218,7 +326,7 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 			}
 		}
 	} else {
-		overwrites, err := CreateOverwrites(ctx.Worker(), ctx.GuildId(), ctx.UserId(), ctx.Worker().BotId, panel)
+		overwrites, err := CreateOverwrites(ctx.Worker(), ctx.GuildId(), openerId, ctx.Worker().BotId, panel)
 		if err != nil {
 			ctx.HandleError(err)
 			return database.Ticket{}, err
@@ -270,7 +378,7 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 		Id:               ticketId,
 		GuildId:          ctx.GuildId(),
 		ChannelId:        &ch.Id,
-		UserId:           ctx.UserId(),
+		UserId:           openerId,
 		Open:             true,
 		OpenTime:         time.Now(), // will be a bit off, but not used
 		WelcomeMessageId: nil,
@@ -279,7 +387,18 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 		JoinMessageId:    joinMessageId,
 	}
 
-	welcomeMessageId, err := SendWelcomeMessage(ctx, ticket, subject, panel, formData)
+	if err := applyPanelLabels(ctx, ticket, panel); err != nil {
+		ctx.HandleError(err)
+	}
+
+	// Allows the opener to be sent updates about their position in the queue, if the welcome message shows it
+	if interactionCtx, ok := ctx.(registry.InteractionContext); ok && openerId == ctx.UserId() {
+		if err := redis.SetQueueInteractionToken(ticket.GuildId, ticket.Id, interactionCtx.InteractionToken()); err != nil {
+			ctx.HandleError(err)
+		}
+	}
+
+	welcomeMessageId, err := SendWelcomeMessage(ctx, ticket, subject, panel, formData, additionalEmbeds...)
 	if err != nil {
 		ctx.HandleError(err)
 	}
@@ -293,6 +412,12 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 		ctx.HandleError(err)
 	}
 
+	if !isThread {
+		if _, err := CreateStaffNotesChannel(ctx, ticket, panel); err != nil {
+			ctx.HandleError(err)
+		}
+	}
+
 	metadata, err := dbclient.Client.GuildMetadata.Get(ctx.GuildId())
 	if err != nil {
 		ctx.HandleError(err)
@@ -348,7 +473,7 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 				ctx.HandleError(err)
 			} else {
 				if shouldMentionUser {
-					content += fmt.Sprintf("<@%d>", ctx.UserId())
+					content += fmt.Sprintf("<@%d>", openerId)
 				}
 			}
 		}
@@ -378,6 +503,25 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 		}
 	}
 
+	openEvent := activity.Event{
+		Type:      redis.ActivityEventOpen,
+		GuildId:   ticket.GuildId,
+		TicketId:  ticket.Id,
+		ChannelId: ch.Id,
+		ActorId:   ctx.UserId(),
+	}
+
+	// Opened by a staff member on behalf of the user
+	if openerId != ctx.UserId() {
+		openEvent.TargetId = openerId
+	}
+
+	activity.Publish(ctx.Worker(), openEvent)
+
+	if err := AutoAssignTicket(ctx, ticket, panel); err != nil {
+		sentry.ErrorWithContext(err, ctx.ToErrorContext())
+	}
+
 	prometheus.LogTicketCreated(ctx.GuildId())
 	statsd.Client.IncrementKey(statsd.KeyTickets)
 	if panel == nil {
@@ -392,12 +536,12 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 	go func() {
 		// retrieve member
 		// GetGuildMember will cache if not already cached
-		if _, err := ctx.Worker().GetGuildMember(ctx.GuildId(), ctx.UserId()); err != nil {
+		if _, err := ctx.Worker().GetGuildMember(ctx.GuildId(), openerId); err != nil {
 			ctx.HandleError(err)
 		}
 
 		// cache user
-		if _, err := ctx.Worker().GetUser(ctx.UserId()); err != nil {
+		if _, err := ctx.Worker().GetUser(openerId); err != nil {
 			ctx.HandleError(err)
 		}
 	}()
@@ -405,42 +549,6 @@ func OpenTicket(ctx registry.CommandContext, panel *database.Panel, subject stri
 	return ticket, nil
 }
 
-// has hit ticket limit, ticket limit
-func getTicketLimit(ctx registry.CommandContext) (bool, int) {
-	isStaff, err := ctx.UserPermissionLevel()
-	if err != nil {
-		sentry.ErrorWithContext(err, ctx.ToErrorContext())
-		return true, 1 // TODO: Stop flow
-	}
-
-	if isStaff >= permcache.Support {
-		return false, 50
-	}
-
-	var openedTickets []database.Ticket
-	var ticketLimit uint8
-
-	group, _ := errgroup.WithContext(context.Background())
-
-	// get ticket limit
-	group.Go(func() (err error) {
-		ticketLimit, err = dbclient.Client.TicketLimit.Get(ctx.GuildId())
-		return
-	})
-
-	group.Go(func() (err error) {
-		openedTickets, err = dbclient.Client.Tickets.GetOpenByUser(ctx.GuildId(), ctx.UserId())
-		return
-	})
-
-	if err := group.Wait(); err != nil {
-		sentry.ErrorWithContext(err, ctx.ToErrorContext())
-		return true, 1
-	}
-
-	return len(openedTickets) >= int(ticketLimit), int(ticketLimit)
-}
-
 func createWebhook(worker *worker.Context, ticketId int, guildId, channelId uint64) {
 	// TODO: Re-add permission check
 	//if permission.HasPermissionsChannel(ctx.Shard, ctx.GuildId, ctx.Shard.SelfId(), channelId, permission.ManageWebhooks) { // Do we actually need this?
@@ -625,8 +733,12 @@ func GenerateChannelName(ctx registry.CommandContext, panel *database.Panel, tic
 			name = fmt.Sprintf("%s-%d", strTicket, ticketId)
 		}
 	} else {
-		var err error
-		name, err = doSubstitutions(ctx, *panel.NamingScheme, openerId, []Substitutor{
+		namingScheme, formAnswers, err := doFormSubstitutions(*panel.NamingScheme, ctx.GuildId(), ticketId)
+		if err != nil {
+			return "", err
+		}
+
+		name, err = doSubstitutions(ctx, namingScheme, openerId, []Substitutor{
 			// %id%
 			NewSubstitutor("id", false, false, func(user user.User, member member.Member) string {
 				return strconv.Itoa(ticketId)
@@ -661,6 +773,8 @@ func GenerateChannelName(ctx registry.CommandContext, panel *database.Panel, tic
 		if err != nil {
 			return "", err
 		}
+
+		name = formAnswers.Replace(name)
 	}
 
 	// Cap length after substitutions
@@ -694,9 +808,10 @@ func BuildJoinThreadMessage(
 	ticketId int,
 	panel *database.Panel,
 	staffMembers []uint64,
+	claimedBy uint64,
 	premiumTier premium.PremiumTier,
 ) command.MessageResponse {
-	return buildJoinThreadMessage(worker, guildId, openerId, ticketId, panel, staffMembers, premiumTier, false)
+	return buildJoinThreadMessage(worker, guildId, openerId, ticketId, panel, staffMembers, claimedBy, premiumTier, false)
 }
 
 func BuildThreadReopenMessage(
@@ -705,9 +820,10 @@ func BuildThreadReopenMessage(
 	ticketId int,
 	panel *database.Panel,
 	staffMembers []uint64,
+	claimedBy uint64,
 	premiumTier premium.PremiumTier,
 ) command.MessageResponse {
-	return buildJoinThreadMessage(worker, guildId, openerId, ticketId, panel, staffMembers, premiumTier, true)
+	return buildJoinThreadMessage(worker, guildId, openerId, ticketId, panel, staffMembers, claimedBy, premiumTier, true)
 }
 
 // TODO: Translations
@@ -717,6 +833,7 @@ func buildJoinThreadMessage(
 	ticketId int,
 	panel *database.Panel,
 	staffMembers []uint64,
+	claimedBy uint64,
 	premiumTier premium.PremiumTier,
 	fromReopen bool,
 ) command.MessageResponse {
@@ -742,6 +859,10 @@ func buildJoinThreadMessage(
 	e.AddField(customisation.PrefixWithEmoji("Panel", customisation.EmojiPanel, !worker.IsWhitelabel), customisation.PrefixWithEmoji(panelName, customisation.EmojiBulletLine, !worker.IsWhitelabel), true)
 	e.AddField(customisation.PrefixWithEmoji("Staff In Ticket", customisation.EmojiStaff, !worker.IsWhitelabel), customisation.PrefixWithEmoji(strconv.Itoa(len(staffMembers)), customisation.EmojiBulletLine, !worker.IsWhitelabel), true)
 
+	if claimedBy != 0 {
+		e.AddField(customisation.PrefixWithEmoji("Claimed By", customisation.EmojiClaim, !worker.IsWhitelabel), customisation.PrefixWithEmoji(fmt.Sprintf("<@%d>", claimedBy), customisation.EmojiBulletLine, !worker.IsWhitelabel), true)
+	}
+
 	if len(staffMembers) > 0 {
 		var mentions []string // dynamic length
 		charCount := len(customisation.EmojiBulletLine.String()) + 1
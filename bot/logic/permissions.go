@@ -2,7 +2,6 @@ package logic
 
 import (
 	"context"
-	"errors"
 	"github.com/TicketsBot/common/collections"
 	"github.com/TicketsBot/database"
 	"github.com/TicketsBot/worker"
@@ -83,8 +82,9 @@ func HasPermissionForTicket(ctx *worker.Context, ticket database.Ticket, userId
 			return false, err
 		}
 
+		// The panel has since been deleted, so treat the ticket as if it was opened without one
 		if panel.PanelId == 0 {
-			return false, errors.New("Panel not found")
+			return IsInDefaultTeam(ticket.GuildId, userId, member)
 		}
 
 		// Check default team, if assigned to panel
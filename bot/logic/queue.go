@@ -0,0 +1,161 @@
+package logic
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/rest"
+	"time"
+)
+
+// Openers are only sent an update once they have moved up at least this many places, or reached the front of the queue
+const queuePositionUpdateThreshold = 3
+
+// GetQueuePosition returns the ticket's position in the queue of open, unclaimed tickets from the same panel, starting
+// from 1
+func GetQueuePosition(ticket database.Ticket) (int, error) {
+	openTickets, err := dbclient.Client.Tickets.GetGuildOpenTickets(ticket.GuildId)
+	if err != nil {
+		return 0, err
+	}
+
+	claimed, err := dbclient.GetClaimedOpenTicketIds(ticket.GuildId)
+	if err != nil {
+		return 0, err
+	}
+
+	return countTicketsAhead(ticket, openTickets, claimed) + 1, nil
+}
+
+// EstimateWaitTime estimates how long a newly opened ticket will wait for a response, from the most recent average first
+// response time. First response times are measured from when the ticket was opened, so they already include the time
+// spent waiting behind other tickets. Returns nil if there is not enough data.
+func EstimateWaitTime(guildId uint64) (*time.Duration, error) {
+	data, err := dbclient.Client.FirstResponseTimeGuildView.Get(guildId)
+	if err != nil {
+		return nil, err
+	}
+
+	responseTime := data.Weekly
+	if responseTime == nil {
+		responseTime = data.Monthly
+	}
+
+	if responseTime == nil {
+		responseTime = data.AllTime
+	}
+
+	return responseTime, nil
+}
+
+// UpdateQueuePositions sends an ephemeral update to the openers of tickets that use the queue placeholders, if their
+// position in the queue has changed significantly. Updates can only be sent for 15 minutes after the ticket was opened.
+func UpdateQueuePositions(ctx registry.CommandContext, guildId uint64) {
+	positions, err := redis.GetQueuePositions(guildId)
+	if err != nil {
+		sentry.ErrorWithContext(err, ctx.ToErrorContext())
+		return
+	}
+
+	if len(positions) == 0 {
+		return
+	}
+
+	openTickets, err := dbclient.Client.Tickets.GetGuildOpenTickets(guildId)
+	if err != nil {
+		sentry.ErrorWithContext(err, ctx.ToErrorContext())
+		return
+	}
+
+	claimed, err := dbclient.GetClaimedOpenTicketIds(guildId)
+	if err != nil {
+		sentry.ErrorWithContext(err, ctx.ToErrorContext())
+		return
+	}
+
+	for ticketId, previousPosition := range positions {
+		if err := updateQueuePosition(ctx, guildId, ticketId, previousPosition, openTickets, claimed); err != nil {
+			sentry.ErrorWithContext(err, ctx.ToErrorContext())
+		}
+	}
+}
+
+func updateQueuePosition(ctx registry.CommandContext, guildId uint64, ticketId, previousPosition int, openTickets []database.Ticket, claimed map[int]bool) error {
+	token, err := redis.GetQueueInteractionToken(guildId, ticketId)
+	if err != nil {
+		return err
+	}
+
+	var ticket database.Ticket
+	for _, openTicket := range openTickets {
+		if openTicket.Id == ticketId {
+			ticket = openTicket
+			break
+		}
+	}
+
+	// The ticket has been closed, or updates can no longer be sent
+	if token == nil || ticket.Id == 0 || ticket.ChannelId == nil {
+		return redis.DeleteQueuePosition(guildId, ticketId)
+	}
+
+	if claimed[ticket.Id] {
+		return redis.DeleteQueuePosition(guildId, ticketId)
+	}
+
+	position := countTicketsAhead(ticket, openTickets, claimed) + 1
+	if previousPosition-position < queuePositionUpdateThreshold && (position > 1 || previousPosition == 1) {
+		return nil
+	}
+
+	if err := redis.SetQueuePosition(guildId, ticketId, position); err != nil {
+		return err
+	}
+
+	estimate, err := EstimateWaitTime(guildId)
+	if err != nil {
+		return err
+	}
+
+	e := utils.BuildEmbedRaw(
+		ctx.GetColour(customisation.Green),
+		ctx.GetMessage(i18n.TitleQueuePosition),
+		ctx.GetMessage(i18n.MessageQueuePositionUpdate, *ticket.ChannelId, position, utils.FormatNullableTime(estimate)),
+		nil,
+		ctx.PremiumTier(),
+	)
+
+	data := rest.WebhookBody{
+		Embeds: utils.Slice(e),
+		Flags:  message.SumFlags(message.FlagEphemeral),
+	}
+
+	_, err = rest.CreateFollowupMessage(*token, ctx.Worker().RateLimiter, ctx.Worker().BotId, data)
+	return err
+}
+
+// Counts the open, unclaimed tickets from the same panel as the ticket that were opened before it
+func countTicketsAhead(ticket database.Ticket, openTickets []database.Ticket, claimed map[int]bool) int {
+	var count int
+	for _, other := range openTickets {
+		if other.Id < ticket.Id && isSamePanel(other.PanelId, ticket.PanelId) && !claimed[other.Id] {
+			count++
+		}
+	}
+
+	return count
+}
+
+func isSamePanel(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/transcripts"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"regexp"
+)
+
+// RedactTranscript applies the guild's redaction rules to the messages in place, returning the number of values that
+// were redacted
+func RedactTranscript(guildId uint64, messages []message.Message) (int, error) {
+	settings, err := redis.GetRedactionSettings(guildId)
+	if err != nil {
+		return 0, err
+	}
+
+	if !settings.Enabled() {
+		return 0, nil
+	}
+
+	var patterns []transcripts.RedactionPattern
+	for _, detector := range redis.RedactionDetectors {
+		if !utils.Contains(settings.Detectors, detector) {
+			continue
+		}
+
+		if pattern, ok := transcripts.BuiltinRedactionPattern(string(detector)); ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	for _, rule := range settings.CustomRules {
+		// Patterns are validated when they are added
+		regex, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			continue
+		}
+
+		patterns = append(patterns, transcripts.RedactionPattern{
+			Name:  rule.Name,
+			Regex: regex,
+		})
+	}
+
+	return transcripts.Redact(messages, patterns), nil
+}
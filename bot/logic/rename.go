@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"errors"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/activity"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/rest"
+)
+
+// RenameTicket renames the ticket's channel
+func RenameTicket(ctx registry.CommandContext, ticket database.Ticket, name string) error {
+	if ticket.ChannelId == nil {
+		return errors.New("channel ID is nil")
+	}
+
+	// Only used for the activity log, so the rename can go ahead without it
+	var previousName string
+	if ch, err := ctx.Worker().GetChannel(*ticket.ChannelId); err == nil {
+		previousName = ch.Name
+	}
+
+	data := rest.ModifyChannelData{
+		Name: name,
+	}
+
+	renamed, err := ctx.Worker().ModifyChannel(*ticket.ChannelId, data)
+	if err != nil {
+		return err
+	}
+
+	activity.Publish(ctx.Worker(), activity.Event{
+		Type:      redis.ActivityEventRename,
+		GuildId:   ticket.GuildId,
+		TicketId:  ticket.Id,
+		ChannelId: *ticket.ChannelId,
+		ActorId:   ctx.UserId(),
+		Previous:  previousName,
+		Current:   renamed.Name,
+	})
+
+	return nil
+}
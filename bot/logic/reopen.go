@@ -2,9 +2,11 @@ package logic
 
 import (
 	"github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/worker/bot/activity"
 	"github.com/TicketsBot/worker/bot/command/registry"
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/rest"
@@ -110,6 +112,14 @@ func ReopenTicket(ctx registry.CommandContext, ticketId int) {
 		return
 	}
 
+	activity.Publish(ctx.Worker(), activity.Event{
+		Type:      redis.ActivityEventReopen,
+		GuildId:   ticket.GuildId,
+		TicketId:  ticket.Id,
+		ChannelId: *ticket.ChannelId,
+		ActorId:   ctx.UserId(),
+	})
+
 	ctx.Reply(customisation.Green, i18n.Success, i18n.MessageReopenSuccess, ticket.Id, *ticket.ChannelId)
 
 	embedData := utils.BuildEmbed(ctx, customisation.Green, i18n.TitleeReopened, i18n.MessageReopenedTicket, nil, ctx.UserId())
@@ -0,0 +1,79 @@
+package logic
+
+import (
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/permission"
+	"github.com/rxdn/gdl/rest"
+	"github.com/rxdn/gdl/rest/request"
+)
+
+// GrantStaffAccess gives a newly added support representative or admin, which may be a user or a role, access to the
+// thread notification channel and to the guild's open ticket channels. Staff are not ticket members, so no member events
+// are published.
+func GrantStaffAccess(ctx registry.CommandContext, id uint64, overwriteType channel.PermissionOverwriteType) error {
+	settings, err := dbclient.Client.Settings.Get(ctx.GuildId())
+	if err != nil {
+		return err
+	}
+
+	if settings.TicketNotificationChannel != nil {
+		// Add user / role to thread notification channel
+		_ = ctx.Worker().EditChannelPermissions(*settings.TicketNotificationChannel, channel.PermissionOverwrite{
+			Id:    id,
+			Type:  overwriteType,
+			Allow: permission.BuildPermissions(permission.ViewChannel, permission.UseApplicationCommands, permission.ReadMessageHistory),
+			Deny:  0,
+		})
+	}
+
+	openTickets, err := dbclient.Client.Tickets.GetGuildOpenTicketsExcludeThreads(ctx.GuildId())
+	if err != nil {
+		return err
+	}
+
+	// Update permissions for existing tickets
+	for _, ticket := range openTickets {
+		if ticket.ChannelId == nil || ticket.IsThread {
+			continue
+		}
+
+		ch, err := ctx.Worker().GetChannel(*ticket.ChannelId)
+		if err != nil {
+			// Check if the channel has been deleted
+			if restError, ok := err.(request.RestError); ok {
+				if restError.StatusCode == 404 {
+					if err := dbclient.Client.Tickets.CloseByChannel(*ticket.ChannelId); err != nil {
+						return err
+					}
+
+					continue
+				} else if restError.StatusCode == 403 {
+					break
+				}
+			}
+
+			return err
+		}
+
+		// Apply overwrites to existing channels
+		overwrites := append(ch.PermissionOverwrites, channel.PermissionOverwrite{
+			Id:    id,
+			Type:  overwriteType,
+			Allow: permission.BuildPermissions(StandardPermissions[:]...),
+			Deny:  0,
+		})
+
+		data := rest.ModifyChannelData{
+			PermissionOverwrites: overwrites,
+			Position:             ch.Position,
+		}
+
+		if _, err = ctx.Worker().ModifyChannel(*ticket.ChannelId, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
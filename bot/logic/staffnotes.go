@@ -0,0 +1,310 @@
+package logic
+
+import (
+	"fmt"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/permission"
+	"github.com/rxdn/gdl/rest"
+	"github.com/rxdn/gdl/rest/request"
+	"strings"
+	"time"
+)
+
+// 1 week, the longest auto archive duration Discord allows
+const staffNotesThreadArchiveDuration = 10080
+
+// CreateStaffNotesChannel creates the private thread or channel that staff can discuss a channel ticket in, if the
+// guild has staff notes enabled. Returns nil if no channel was created.
+func CreateStaffNotesChannel(ctx registry.CommandContext, ticket database.Ticket, panel *database.Panel) (*uint64, error) {
+	if ticket.IsThread || ticket.ChannelId == nil {
+		return nil, nil
+	}
+
+	settings, err := redis.GetStaffNotesSettings(ticket.GuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	allowedUsers, allowedRoles, err := getAllowedUsersRoles(ticket.GuildId, ctx.Worker().BotId, panel)
+	if err != nil {
+		return nil, err
+	}
+
+	var ch channel.Channel
+	if settings.Mode == redis.StaffNotesModeChannel {
+		overwrites := []channel.PermissionOverwrite{ // @everyone
+			{
+				Id:    ticket.GuildId,
+				Type:  channel.PermissionTypeRole,
+				Allow: 0,
+				Deny:  permission.BuildPermissions(permission.ViewChannel),
+			},
+		}
+
+		for _, userId := range allowedUsers {
+			overwrites = append(overwrites, channel.PermissionOverwrite{
+				Id:    userId,
+				Type:  channel.PermissionTypeMember,
+				Allow: permission.BuildPermissions(StandardPermissions[:]...),
+				Deny:  0,
+			})
+		}
+
+		for _, roleId := range allowedRoles {
+			overwrites = append(overwrites, channel.PermissionOverwrite{
+				Id:    roleId,
+				Type:  channel.PermissionTypeRole,
+				Allow: permission.BuildPermissions(StandardPermissions[:]...),
+				Deny:  0,
+			})
+		}
+
+		data := rest.CreateChannelData{
+			Name:                 fmt.Sprintf("notes-%d", ticket.Id),
+			Type:                 channel.ChannelTypeGuildText,
+			Topic:                fmt.Sprintf("Staff notes for ticket #%d", ticket.Id),
+			PermissionOverwrites: overwrites,
+		}
+
+		if settings.CategoryId != nil {
+			data.ParentId = *settings.CategoryId
+		} else {
+			ticketChannel, err := ctx.Worker().GetChannel(*ticket.ChannelId)
+			if err != nil {
+				return nil, err
+			}
+
+			data.ParentId = ticketChannel.ParentId.Value
+		}
+
+		ch, err = ctx.Worker().CreateGuildChannel(ticket.GuildId, data)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ch, err = ctx.Worker().CreatePrivateThread(*ticket.ChannelId, "staff-notes", staffNotesThreadArchiveDuration, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Mentioning users and roles in a private thread adds them to it, without the need to add each member of the
+		// roles individually
+		var content string
+		for _, userId := range allowedUsers {
+			if userId != ctx.Worker().BotId {
+				content += fmt.Sprintf("<@%d>", userId)
+			}
+		}
+
+		for _, roleId := range allowedRoles {
+			content += fmt.Sprintf("<@&%d>", roleId)
+		}
+
+		if content != "" {
+			if len(content) > 2000 {
+				content = content[:2000]
+			}
+
+			mentionMessage, err := ctx.Worker().CreateMessageComplex(ch.Id, rest.CreateMessageData{
+				Content: content,
+				AllowedMentions: message.AllowedMention{
+					Parse: []message.AllowedMentionType{
+						message.USERS,
+						message.ROLES,
+					},
+				},
+			})
+
+			if err == nil {
+				_ = ctx.Worker().DeleteMessage(ch.Id, mentionMessage.Id)
+			}
+		}
+	}
+
+	if err := redis.SetStaffNotesChannel(ticket.GuildId, ticket.Id, ch.Id); err != nil {
+		return nil, err
+	}
+
+	return &ch.Id, nil
+}
+
+// PostStaffNote posts a note in the ticket's staff notes thread or channel, creating it if it does not exist yet.
+// Returns nil if staff notes are disabled.
+func PostStaffNote(ctx registry.CommandContext, ticket database.Ticket, content string) (*uint64, error) {
+	channelId, err := getOrCreateStaffNotesChannel(ctx, ticket)
+	if err != nil || channelId == nil {
+		return nil, err
+	}
+
+	author, err := ctx.User()
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := ctx.Worker().GetChannel(*channelId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the author can see their note, if they were not added to the thread when it was created
+	if ch.Type == channel.ChannelTypeGuildPrivateThread {
+		if err := ctx.Worker().AddThreadMember(*channelId, ctx.UserId()); err != nil {
+			return nil, err
+		}
+	}
+
+	e := embed.NewEmbed().
+		SetAuthor(author.Username, "", author.AvatarUrl(256)).
+		SetColor(ctx.GetColour(customisation.Blue)).
+		SetDescription(content).
+		SetTimestamp(time.Now())
+
+	if _, err := ctx.Worker().CreateMessageEmbed(*channelId, e); err != nil {
+		return nil, err
+	}
+
+	return channelId, nil
+}
+
+func getOrCreateStaffNotesChannel(ctx registry.CommandContext, ticket database.Ticket) (*uint64, error) {
+	channelId, err := redis.GetStaffNotesChannel(ticket.GuildId, ticket.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if channelId != nil {
+		if _, err := ctx.Worker().GetChannel(*channelId); err == nil {
+			return channelId, nil
+		} else if restError, ok := err.(request.RestError); !ok || restError.StatusCode != 404 {
+			return nil, err
+		}
+	}
+
+	// The ticket was opened before staff notes were enabled, or the notes channel was deleted
+	var panel *database.Panel
+	if ticket.PanelId != nil {
+		tmp, err := dbclient.Client.Panel.GetById(*ticket.PanelId)
+		if err != nil {
+			return nil, err
+		}
+
+		if tmp.PanelId != 0 && tmp.GuildId == ticket.GuildId {
+			panel = &tmp
+		}
+	}
+
+	return CreateStaffNotesChannel(ctx, ticket, panel)
+}
+
+// Retrieves the messages sent in the ticket's staff notes thread or channel, in the order they were sent, and deletes
+// the notes channel. Must be called before the ticket channel is deleted, as threads are deleted with it.
+func collectStaffNotes(ctx registry.CommandContext, ticket database.Ticket) ([]message.Message, error) {
+	channelId, err := redis.GetStaffNotesChannel(ticket.GuildId, ticket.Id)
+	if err != nil || channelId == nil {
+		return nil, err
+	}
+
+	var notes []message.Message
+
+	lastId := uint64(0)
+	for {
+		array, err := ctx.Worker().GetChannelMessages(*channelId, rest.GetChannelMessagesData{
+			Before: lastId,
+			Limit:  100,
+		})
+
+		if err != nil {
+			// The notes channel was deleted manually
+			if restError, ok := err.(request.RestError); ok && restError.StatusCode == 404 {
+				break
+			}
+
+			return nil, err
+		}
+
+		if len(array) == 0 {
+			break
+		}
+
+		lastId = array[len(array)-1].Id
+		notes = append(notes, array...)
+	}
+
+	// Reverse messages
+	for i, j := 0, len(notes)-1; i < j; i, j = i+1, j-1 {
+		notes[i], notes[j] = notes[j], notes[i]
+	}
+
+	// Private threads are deleted or archived along with the ticket channel
+	if ch, err := ctx.Worker().GetChannel(*channelId); err == nil && ch.Type == channel.ChannelTypeGuildText {
+		if _, err := ctx.Worker().DeleteChannel(*channelId); err != nil {
+			return notes, err
+		}
+	}
+
+	return notes, redis.DeleteStaffNotesChannel(ticket.GuildId, ticket.Id)
+}
+
+// Renders the staff notes as a text file, to be attached to the transcript log in the archive channel, which only staff
+// can see
+func buildStaffNotesFile(ticket database.Ticket, notes []message.Message) *rest.File {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Staff notes for ticket #%d\n\n", ticket.Id))
+
+	var count int
+	for _, note := range notes {
+		author := note.Author.Username
+		content := note.Content
+
+		// Notes posted with /note are embeds sent by the bot, on behalf of the staff member
+		for _, e := range note.Embeds {
+			if e.Author != nil {
+				author = e.Author.Name
+			}
+
+			if e.Description != "" {
+				if content != "" {
+					content += "\n"
+				}
+
+				content += e.Description
+			}
+		}
+
+		for _, attachment := range note.Attachments {
+			if content != "" {
+				content += "\n"
+			}
+
+			content += attachment.Url
+		}
+
+		if content == "" {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("[%s] %s: %s\n", note.Timestamp.UTC().Format("2006-01-02 15:04:05"), author, content))
+		count++
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	return &rest.File{
+		Name:        fmt.Sprintf("staff-notes-%d.txt", ticket.Id),
+		ContentType: "text/plain",
+		Reader:      strings.NewReader(sb.String()),
+	}
+}
@@ -0,0 +1,41 @@
+package logic
+
+import (
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/activity"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+)
+
+// SetTicketPanel assigns the ticket to the panel in the database. Updating the channel's permissions and category is
+// left to the caller.
+func SetTicketPanel(ctx registry.CommandContext, ticket database.Ticket, panel database.Panel) error {
+	// Only used for the activity log
+	previousPanel := "None"
+	if ticket.PanelId != nil {
+		if tmp, err := dbclient.Client.Panel.GetById(*ticket.PanelId); err == nil && tmp.PanelId != 0 {
+			previousPanel = tmp.Title
+		}
+	}
+
+	if err := dbclient.Client.Tickets.SetPanelId(ticket.GuildId, ticket.Id, panel.PanelId); err != nil {
+		return err
+	}
+
+	event := activity.Event{
+		Type:     redis.ActivityEventPanelSwitch,
+		GuildId:  ticket.GuildId,
+		TicketId: ticket.Id,
+		ActorId:  ctx.UserId(),
+		Previous: previousPanel,
+		Current:  panel.Title,
+	}
+
+	if ticket.ChannelId != nil {
+		event.ChannelId = *ticket.ChannelId
+	}
+
+	activity.Publish(ctx.Worker(), event)
+	return nil
+}
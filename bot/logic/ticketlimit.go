@@ -0,0 +1,118 @@
+package logic
+
+import (
+	"context"
+	permcache "github.com/TicketsBot/common/permission"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/i18n"
+	"golang.org/x/sync/errgroup"
+)
+
+// CheckTicketLimits returns whether the user can open another ticket from the panel (nil if not using a panel),
+// replying to the user if not. Staff are exempt from all limits.
+func CheckTicketLimits(ctx registry.CommandContext, panel *database.Panel) (bool, error) {
+	permissionLevel, err := ctx.UserPermissionLevel()
+	if err != nil {
+		return false, err
+	}
+
+	if permissionLevel >= permcache.Support {
+		return true, nil
+	}
+
+	var openedTickets []database.Ticket
+	var guildLimit uint8
+	var limits redis.TicketLimits
+
+	group, _ := errgroup.WithContext(context.Background())
+
+	group.Go(func() (err error) {
+		guildLimit, err = dbclient.Client.TicketLimit.Get(ctx.GuildId())
+		return
+	})
+
+	group.Go(func() (err error) {
+		openedTickets, err = dbclient.Client.Tickets.GetOpenByUser(ctx.GuildId(), ctx.UserId())
+		return
+	})
+
+	group.Go(func() (err error) {
+		limits, err = redis.GetTicketLimits(ctx.GuildId())
+		return
+	})
+
+	if err := group.Wait(); err != nil {
+		return false, err
+	}
+
+	// Tickets opened without a panel are all handled by the default team, so are duplicates of each other
+	var panelTickets []database.Ticket
+	for _, ticket := range openedTickets {
+		if panel == nil && ticket.PanelId == nil || panel != nil && ticket.PanelId != nil && *ticket.PanelId == panel.PanelId {
+			panelTickets = append(panelTickets, ticket)
+		}
+	}
+
+	if limits.RedirectDuplicates {
+		for _, ticket := range panelTickets {
+			if ticket.ChannelId != nil {
+				ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTicketDuplicate, *ticket.ChannelId)
+				return false, nil
+			}
+		}
+	}
+
+	limit, err := getUserTicketLimit(ctx, int(guildLimit), limits)
+	if err != nil {
+		return false, err
+	}
+
+	if len(openedTickets) >= limit {
+		ticketsPluralised := "ticket"
+		if limit > 1 {
+			ticketsPluralised += "s"
+		}
+
+		// TODO: Use translation of tickets
+		ctx.Reply(customisation.Red, i18n.Error, i18n.MessageTicketLimitReached, limit, ticketsPluralised)
+		return false, nil
+	}
+
+	if panel != nil {
+		if panelLimit, ok := limits.PanelLimits[panel.PanelId]; ok && len(panelTickets) >= panelLimit {
+			ctx.Reply(customisation.Red, i18n.Error, i18n.MessagePanelTicketLimitReached, panelLimit, panel.Title)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Returns the highest limit of the user's roles, or the guild-wide limit if they have none with a limit
+func getUserTicketLimit(ctx registry.CommandContext, guildLimit int, limits redis.TicketLimits) (int, error) {
+	if len(limits.RoleLimits) == 0 {
+		return guildLimit, nil
+	}
+
+	member, err := ctx.Member()
+	if err != nil {
+		return 0, err
+	}
+
+	limit, found := 0, false
+	for _, roleId := range member.Roles {
+		if roleLimit, ok := limits.RoleLimits[roleId]; ok && (!found || roleLimit > limit) {
+			limit, found = roleLimit, true
+		}
+	}
+
+	if !found {
+		return guildLimit, nil
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,201 @@
+package logic
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/database"
+	"github.com/TicketsBot/worker"
+	"github.com/TicketsBot/worker/bot/command"
+	"github.com/TicketsBot/worker/bot/command/registry"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/transcripts"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/TicketsBot/worker/config"
+	"github.com/TicketsBot/worker/i18n"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/objects/interaction/component"
+	"strings"
+	"time"
+)
+
+const (
+	TicketListPageSize = 10
+
+	// How many tickets to fetch at once, as claims and date ranges are filtered after querying
+	ticketListBatchSize = 100
+)
+
+// TranscriptUrl returns a link to the transcript on the dashboard, which requires the reader to log in
+func TranscriptUrl(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("https://panel.ticketsbot.net/manage/%d/transcripts/view/%d", guildId, ticketId)
+}
+
+// SignedTranscriptUrl returns a time limited link to the transcript that does not require the reader to log in, for
+// sending to the ticket opener. Whitelabel bots with their own viewer link to it; otherwise the viewer in the config is
+// used. Falls back to the dashboard link if neither is configured.
+func SignedTranscriptUrl(worker *worker.Context, guildId uint64, ticketId int) string {
+	conf := config.Conf.Transcripts
+	viewerUrl, signingKey := conf.ViewerUrl, conf.SigningKey
+
+	if worker.IsWhitelabel {
+		viewer, err := redis.GetTranscriptViewer(worker.BotId)
+		if err != nil {
+			sentry.Error(err)
+		} else if viewer != nil {
+			viewerUrl, signingKey = viewer.Url, viewer.SigningKey
+		}
+	}
+
+	if viewerUrl == "" || signingKey == "" {
+		return TranscriptUrl(guildId, ticketId)
+	}
+
+	return transcripts.SignTranscriptUrl(viewerUrl, []byte(signingKey), guildId, ticketId, time.Now().Add(conf.LinkExpiry))
+}
+
+// FindTickets returns the tickets on the given page (starting from 0), newest first, and whether there is another page
+func FindTickets(guildId uint64, filter redis.TicketListFilter, page int) ([]database.Ticket, bool, error) {
+	options := database.TicketQueryOptions{
+		GuildId: guildId,
+		Open:    filter.Open,
+		Order:   database.OrderTypeDescending,
+		Limit:   ticketListBatchSize,
+	}
+
+	if filter.UserId != nil {
+		options.UserIds = []uint64{*filter.UserId}
+	}
+
+	if filter.PanelId != nil {
+		options.PanelId = *filter.PanelId
+	}
+
+	// Load the member's claims up front, rather than looking up the claim of every ticket scanned
+	var claimed map[int]bool
+	var oldestClaim int
+	if filter.ClaimedBy != nil {
+		var err error
+		claimed, err = dbclient.GetClaimedTicketIds(guildId, *filter.ClaimedBy)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if len(claimed) == 0 {
+			return nil, false, nil
+		}
+
+		for ticketId := range claimed {
+			if oldestClaim == 0 || ticketId < oldestClaim {
+				oldestClaim = ticketId
+			}
+		}
+	}
+
+	toSkip := page * TicketListPageSize
+
+	var matched []database.Ticket
+	for {
+		tickets, err := dbclient.Client.Tickets.GetByOptions(options)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, ticket := range tickets {
+			// Tickets are ordered by ID, which increases with open time, so no later tickets can match either
+			if (filter.From != nil && ticket.OpenTime.Before(*filter.From)) || ticket.Id < oldestClaim {
+				return matched, false, nil
+			}
+
+			if filter.To != nil && ticket.OpenTime.After(*filter.To) {
+				continue
+			}
+
+			if claimed != nil && !claimed[ticket.Id] {
+				continue
+			}
+
+			if toSkip > 0 {
+				toSkip--
+				continue
+			}
+
+			// A further match after a full page means there is a next page
+			if len(matched) == TicketListPageSize {
+				return matched, true, nil
+			}
+
+			matched = append(matched, ticket)
+		}
+
+		if len(tickets) < ticketListBatchSize {
+			return matched, false, nil
+		}
+
+		options.Offset += ticketListBatchSize
+	}
+}
+
+// BuildTicketListResponse renders a page of tickets, with buttons to move between pages. filterId is the ID the filter
+// is stored in redis under.
+func BuildTicketListResponse(ctx registry.CommandContext, filterId string, filter redis.TicketListFilter, page int) (command.MessageResponse, error) {
+	tickets, hasNext, err := FindTickets(ctx.GuildId(), filter, page)
+	if err != nil {
+		return command.MessageResponse{}, err
+	}
+
+	var lines []string
+	for _, ticket := range tickets {
+		lines = append(lines, formatTicketListEntry(ctx, ticket))
+	}
+
+	var content string
+	if len(lines) == 0 {
+		content = ctx.GetMessage(i18n.MessageTicketListEmpty)
+	} else {
+		content = strings.Join(lines, "\n")
+	}
+
+	content += "\n\n" + ctx.GetMessage(i18n.MessageTicketListPage, page+1)
+
+	e := utils.BuildEmbedRaw(ctx.GetColour(customisation.Green), ctx.GetMessage(i18n.TitleTickets), content, nil, ctx.PremiumTier())
+
+	components := utils.Slice(component.BuildActionRow(
+		component.BuildButton(component.Button{
+			Label:    ctx.GetMessage(i18n.MessageTicketListPrevious),
+			CustomId: fmt.Sprintf("ticketlist_%s_%d", filterId, page-1),
+			Style:    component.ButtonStyleSecondary,
+			Disabled: page == 0,
+		}),
+		component.BuildButton(component.Button{
+			Label:    ctx.GetMessage(i18n.MessageTicketListNext),
+			CustomId: fmt.Sprintf("ticketlist_%s_%d", filterId, page+1),
+			Style:    component.ButtonStyleSecondary,
+			Disabled: !hasNext,
+		}),
+	))
+
+	return command.NewEphemeralEmbedMessageResponseWithComponents(e, components), nil
+}
+
+func formatTicketListEntry(ctx registry.CommandContext, ticket database.Ticket) string {
+	var link string
+	if ticket.Open && ticket.ChannelId != nil {
+		link = fmt.Sprintf("<#%d>", *ticket.ChannelId)
+	} else if ticket.HasTranscript {
+		link = fmt.Sprintf("[%s](%s)", ctx.GetMessage(i18n.MessageTicketListTranscript), TranscriptUrl(ticket.GuildId, ticket.Id))
+	} else {
+		link = ctx.GetMessage(i18n.MessageTicketListNoTranscript)
+	}
+
+	return fmt.Sprintf("`#%d` <@%d> • %s • %s • %s", ticket.Id, ticket.UserId, TicketStatus(ctx, ticket), message.BuildTimestamp(ticket.OpenTime, message.TimestampStyleShortDate), link)
+}
+
+func TicketStatus(ctx registry.CommandContext, ticket database.Ticket) string {
+	if ticket.Open {
+		return ctx.GetMessage(i18n.MessageTicketListOpen)
+	} else {
+		return ctx.GetMessage(i18n.MessageTicketListClosed)
+	}
+}
@@ -0,0 +1,149 @@
+package logic
+
+import (
+	"fmt"
+	"github.com/TicketsBot/worker/bot/customisation"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/objects/user"
+	"sort"
+	"time"
+)
+
+// Embeds can have at most 25 fields, so only the most recent edits are shown
+const maxTranscriptEditHistory = 25
+
+type loggedMessage struct {
+	message.Message
+	edits     []messageEdit
+	deleted   bool
+	deletedAt time.Time
+	deletedBy *user.User
+}
+
+type messageEdit struct {
+	content  string
+	editedAt time.Time
+}
+
+// BuildTranscriptFromLog builds the transcript from the log of messages captured as they were sent, which includes
+// previous versions of edited messages, and messages that have been deleted, without having to page through the
+// channel history. Returns false if the log is missing or incomplete, e.g. if the ticket was opened before logs were
+// enabled, in which case the channel history must be used instead.
+func BuildTranscriptFromLog(guildId uint64, ticketId int, botId uint64) ([]message.Message, bool, error) {
+	events, err := redis.GetTranscriptLog(guildId, ticketId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(events) == 0 || events[0].Type != redis.TranscriptLogEventStart {
+		return nil, false, nil
+	}
+
+	messages := make(map[uint64]*loggedMessage)
+	for _, event := range events[1:] {
+		switch event.Type {
+		case redis.TranscriptLogEventCreate:
+			// The welcome message is logged when sent, as well as by the listener
+			if _, ok := messages[event.MessageId]; !ok && event.Message != nil {
+				messages[event.MessageId] = &loggedMessage{Message: *event.Message}
+			}
+		case redis.TranscriptLogEventUpdate:
+			if logged, ok := messages[event.MessageId]; ok && event.Message != nil {
+				logged.applyUpdate(*event.Message, event.Time)
+			}
+		case redis.TranscriptLogEventDelete:
+			if logged, ok := messages[event.MessageId]; ok {
+				logged.deleted = true
+				logged.deletedAt = event.Time
+
+				if event.DeletedBy != nil && event.DeletedByTarget == logged.Author.Id {
+					logged.deletedBy = event.DeletedBy
+				}
+			}
+		}
+	}
+
+	var transcript []message.Message
+	for _, logged := range messages {
+		// Messages we delete ourselves, such as mention pings, were never meant to be seen
+		if logged.deleted && logged.deletedBy == nil && logged.Author.Id == botId {
+			continue
+		}
+
+		transcript = append(transcript, logged.build())
+	}
+
+	// Snowflakes increase with time
+	sort.Slice(transcript, func(i, j int) bool {
+		return transcript[i].Id < transcript[j].Id
+	})
+
+	return transcript, true, nil
+}
+
+func (m *loggedMessage) applyUpdate(update message.Message, receivedAt time.Time) {
+	previousContent := m.Content
+	if !redis.ApplyMessageUpdate(&m.Message, update) {
+		return
+	}
+
+	// Bots edit their messages to show state, e.g. the claim status, which is not worth keeping a history of
+	if !m.Author.Bot && m.Content != previousContent {
+		m.edits = append(m.edits, messageEdit{
+			content:  previousContent,
+			editedAt: receivedAt,
+		})
+	}
+}
+
+// Returns the message, with embeds appended to show its edit history and deletion
+func (m *loggedMessage) build() message.Message {
+	msg := m.Message
+
+	if len(m.edits) > 0 {
+		e := embed.NewEmbed().
+			SetTitle("Edit History").
+			SetColor(customisation.DefaultColours[customisation.Orange])
+
+		edits := m.edits
+		if len(edits) > maxTranscriptEditHistory {
+			edits = edits[len(edits)-maxTranscriptEditHistory:]
+		}
+
+		for _, edit := range edits {
+			content := edit.content
+			if content == "" {
+				content = "*No content*"
+			}
+
+			e.AddField(formatTranscriptTime(edit.editedAt), utils.StringMax(content, 1024), false)
+		}
+
+		msg.Embeds = append(msg.Embeds, *e)
+	}
+
+	if m.deleted {
+		var description string
+		if m.deletedBy == nil {
+			description = fmt.Sprintf("Deleted at %s", formatTranscriptTime(m.deletedAt))
+		} else {
+			description = fmt.Sprintf("Deleted by %s (%d) at %s", m.deletedBy.Username, m.deletedBy.Id, formatTranscriptTime(m.deletedAt))
+		}
+
+		e := embed.NewEmbed().
+			SetTitle("Message Deleted").
+			SetColor(customisation.DefaultColours[customisation.Red]).
+			SetDescription(description)
+
+		msg.Embeds = append(msg.Embeds, *e)
+	}
+
+	return msg
+}
+
+func formatTranscriptTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
@@ -0,0 +1,147 @@
+package logic
+
+import (
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/dbclient"
+	"github.com/TicketsBot/worker/bot/errorcontext"
+	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/utils"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"time"
+)
+
+const (
+	transcriptOutboxInitialBackoff = time.Minute
+	transcriptOutboxMaxBackoff     = time.Hour * 6
+	// After this many failed attempts, the upload must be replayed manually by an admin
+	TranscriptOutboxMaxAttempts = 15
+)
+
+// QueueTranscriptUpload saves the messages of a transcript that failed to upload, so that the upload can be retried
+// after the ticket channel has been deleted. If attachmentLimit is non-zero, attachments are preserved before the retry.
+func QueueTranscriptUpload(guildId uint64, ticketId int, premium bool, attachmentLimit int, messages []message.Message, uploadErr error) error {
+	now := time.Now()
+	nextAttempt := now.Add(transcriptOutboxInitialBackoff)
+
+	entry := redis.TranscriptOutboxEntry{
+		GuildId:         guildId,
+		TicketId:        ticketId,
+		Premium:         premium,
+		Attempts:        1,
+		LastError:       uploadErr.Error(),
+		CreatedAt:       now,
+		NextAttempt:     &nextAttempt,
+		AttachmentLimit: attachmentLimit,
+	}
+
+	return redis.AddTranscriptOutbox(entry, messages)
+}
+
+// QueueAttachmentPreservation saves the messages of a transcript that has already been uploaded, so that its
+// attachments can be preserved, and the transcript uploaded again to link to the copies. Downloading attachments can
+// take minutes, so it is started in the background rather than holding up the close. If the worker dies first, the
+// outbox listener picks the entry up instead.
+func QueueAttachmentPreservation(guildId uint64, ticketId int, premium bool, attachmentLimit int, messages []message.Message) error {
+	now := time.Now()
+
+	entry := redis.TranscriptOutboxEntry{
+		GuildId:         guildId,
+		TicketId:        ticketId,
+		Premium:         premium,
+		CreatedAt:       now,
+		NextAttempt:     &now,
+		AttachmentLimit: attachmentLimit,
+	}
+
+	if err := redis.AddTranscriptOutbox(entry, messages); err != nil {
+		return err
+	}
+
+	go func() {
+		entry, ok, err := redis.ClaimTranscriptOutbox(guildId, ticketId)
+		if err != nil {
+			sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{Guild: guildId})
+			return
+		}
+
+		// Already claimed by the outbox listener
+		if !ok {
+			return
+		}
+
+		if err := RetryTranscriptUpload(entry); err != nil {
+			sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{Guild: guildId})
+		}
+	}()
+
+	return nil
+}
+
+// RetryTranscriptUpload attempts to upload a transcript from the outbox. The entry must have been claimed from the
+// outbox first. On failure, the entry is rescheduled with exponential backoff, and the error is returned.
+func RetryTranscriptUpload(entry redis.TranscriptOutboxEntry) error {
+	if uploadErr := uploadOutboxTranscript(&entry); uploadErr != nil {
+		entry.Attempts++
+		entry.LastError = uploadErr.Error()
+
+		if entry.Attempts < TranscriptOutboxMaxAttempts {
+			nextAttempt := time.Now().Add(transcriptOutboxBackoff(entry.Attempts))
+			entry.NextAttempt = &nextAttempt
+		} else {
+			entry.NextAttempt = nil
+		}
+
+		if err := redis.UpdateTranscriptOutbox(entry); err != nil {
+			return err
+		}
+
+		return uploadErr
+	}
+
+	if err := redis.DeleteTranscriptLog(entry.GuildId, entry.TicketId); err != nil {
+		return err
+	}
+
+	return redis.DeleteTranscriptOutbox(entry.GuildId, entry.TicketId)
+}
+
+func uploadOutboxTranscript(entry *redis.TranscriptOutboxEntry) error {
+	messages, err := redis.GetTranscriptOutboxMessages(entry.GuildId, entry.TicketId)
+	if err != nil {
+		return err
+	}
+
+	if entry.AttachmentLimit > 0 {
+		// Attachments that fail to download keep their original URLs, rather than holding up the upload
+		if _, err := preserveAttachments(entry.GuildId, entry.TicketId, entry.AttachmentLimit, messages); err != nil {
+			sentry.ErrorWithContext(err, errorcontext.WorkerErrorContext{Guild: entry.GuildId})
+		}
+
+		// Saved before uploading, so that the attachments are not downloaded again if the upload fails
+		if err := redis.SetTranscriptOutboxMessages(entry.GuildId, entry.TicketId, messages); err != nil {
+			return err
+		}
+
+		entry.AttachmentLimit = 0
+	}
+
+	if err := utils.TranscriptStore.Store(messages, entry.GuildId, entry.TicketId, entry.Premium); err != nil {
+		return err
+	}
+
+	// Only marked once the upload has succeeded, so that the transcript is never linked to before it exists
+	return dbclient.Client.Tickets.SetHasTranscript(entry.GuildId, entry.TicketId, true)
+}
+
+// Returns the delay before the next attempt, after the given number of failed attempts
+func transcriptOutboxBackoff(attempts int) time.Duration {
+	backoff := transcriptOutboxInitialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= transcriptOutboxMaxBackoff {
+			return transcriptOutboxMaxBackoff
+		}
+	}
+
+	return backoff
+}
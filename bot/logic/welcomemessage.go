@@ -13,6 +13,7 @@ import (
 	"github.com/TicketsBot/worker/bot/customisation"
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/integrations"
+	"github.com/TicketsBot/worker/bot/redis"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/i18n"
 	"github.com/rxdn/gdl/objects/channel/embed"
@@ -27,8 +28,8 @@ import (
 	"time"
 )
 
-// returns msg id
-func SendWelcomeMessage(ctx registry.CommandContext, ticket database.Ticket, subject string, panel *database.Panel, formData map[database.FormInput]string) (uint64, error) {
+// returns msg id. additionalEmbeds are sent after the form answers
+func SendWelcomeMessage(ctx registry.CommandContext, ticket database.Ticket, subject string, panel *database.Panel, formData map[database.FormInput]string, additionalEmbeds ...*embed.Embed) (uint64, error) {
 	settings, err := dbclient.Client.Settings.Get(ticket.GuildId)
 	if err != nil {
 		return 0, err
@@ -59,6 +60,8 @@ func SendWelcomeMessage(ctx registry.CommandContext, ticket database.Ticket, sub
 		embeds = append(embeds, formAnswersEmbed)
 	}
 
+	embeds = append(embeds, additionalEmbeds...)
+
 	buttons := []component.Component{
 		component.BuildButton(component.Button{
 			Label:    ctx.GetMessage(i18n.TitleClose),
@@ -74,7 +77,7 @@ func SendWelcomeMessage(ctx registry.CommandContext, ticket database.Ticket, sub
 		}),
 	}
 
-	if !settings.HideClaimButton && !ticket.IsThread {
+	if !settings.HideClaimButton {
 		buttons = append(buttons, component.BuildButton(component.Button{
 			Label:    ctx.GetMessage(i18n.TitleClaim),
 			CustomId: "claim",
@@ -100,6 +103,18 @@ func SendWelcomeMessage(ctx registry.CommandContext, ticket database.Ticket, sub
 		return 0, err
 	}
 
+	// The message listener cannot find the ticket until the channel has been stored, so log the message here
+	event := redis.TranscriptLogEvent{
+		Type:      redis.TranscriptLogEventCreate,
+		MessageId: msg.Id,
+		Message:   &msg,
+		Time:      time.Now(),
+	}
+
+	if err := redis.AppendTranscriptLog(ticket.GuildId, ticket.Id, event); err != nil {
+		ctx.HandleError(err)
+	}
+
 	return msg.Id, nil
 }
 
@@ -136,6 +151,15 @@ func BuildWelcomeMessageEmbed(ctx registry.CommandContext, ticket database.Ticke
 }
 
 func DoPlaceholderSubstitutions(message string, ctx *worker.Context, ticket database.Ticket) string {
+	message, formAnswers, err := doFormSubstitutions(message, ticket.GuildId, ticket.Id)
+	if err != nil {
+		sentry.Error(err)
+	}
+
+	return formAnswers.Replace(doPlaceholderSubstitutions(message, ctx, ticket))
+}
+
+func doPlaceholderSubstitutions(message string, ctx *worker.Context, ticket database.Ticket) string {
 	var lock sync.Mutex
 
 	// do DB lookups in parallel
@@ -244,13 +268,19 @@ func DoPlaceholderSubstitutions(message string, ctx *worker.Context, ticket data
 			return message
 		}
 
+		formAnswers, err := redis.GetTicketFormAnswers(ticket.GuildId, ticket.Id)
+		if err != nil {
+			sentry.Error(err)
+			return message
+		}
+
 		// Replace placeholders
 		for _, integration := range usedIntegrations {
 			integration := integration
 			integrationSecrets := secrets[integration.Id]
 
 			group.Go(func() error {
-				response, err := integrations.Fetch(integration, ticket, integrationSecrets, headers[integration.Id], placeholderMap[integration.Id])
+				response, err := integrations.Fetch(integration, ticket, integrationSecrets, headers[integration.Id], placeholderMap[integration.Id], formAnswers)
 				if err != nil {
 					return err
 				}
@@ -385,6 +415,70 @@ var groupSubstitutions = []GroupSubstitutor{
 			}
 		},
 	),
+	NewGroupSubstitutor([]string{"queue_position", "estimated_wait"},
+		func(ctx *worker.Context, ticket database.Ticket) map[string]string {
+			position, err := GetQueuePosition(ticket)
+			if err != nil {
+				sentry.Error(err)
+				return nil
+			}
+
+			// Remember the position the opener was told, so they can be updated when it changes
+			if err := redis.SetQueuePosition(ticket.GuildId, ticket.Id, position); err != nil {
+				sentry.Error(err)
+			}
+
+			estimate, err := EstimateWaitTime(ticket.GuildId)
+			if err != nil {
+				sentry.Error(err)
+			}
+
+			return map[string]string{
+				"queue_position": strconv.Itoa(position),
+				"estimated_wait": utils.FormatNullableTime(estimate),
+			}
+		},
+	),
+}
+
+// Replaces %form:name% placeholders with tokens, and returns a replacer that swaps the tokens for the ticket's form
+// answers. Answers are user input, so must only be inserted once every other placeholder has been replaced, otherwise
+// an answer containing a placeholder would be expanded.
+func doFormSubstitutions(message string, guildId uint64, ticketId int) (string, *strings.Replacer, error) {
+	if !utils.ContainsFormPlaceholder(message) {
+		return message, strings.NewReplacer(), nil
+	}
+
+	answers, err := redis.GetTicketFormAnswers(guildId, ticketId)
+	if err != nil {
+		return message, strings.NewReplacer(), err
+	}
+
+	var replacements []string
+	message = utils.ReplaceFormPlaceholders(message, func(name string) string {
+		answer, ok := answers[name]
+		if !ok || answer == "" {
+			answer = "N/A"
+		}
+
+		// Placeholders cannot contain null characters, so the tokens cannot be created by any other substitution
+		token := fmt.Sprintf("\x00%d\x00", len(replacements)/2)
+		replacements = append(replacements, token, answer)
+
+		return token
+	})
+
+	return message, strings.NewReplacer(replacements...), nil
+}
+
+// Returns the answers keyed by their placeholder name
+func getFormAnswerPlaceholders(formData map[database.FormInput]string) map[string]string {
+	answers := make(map[string]string, len(formData))
+	for input, answer := range formData {
+		answers[utils.FormPlaceholderName(input.Label)] = answer
+	}
+
+	return answers
 }
 
 func getFormDataFields(formData map[database.FormInput]string) []embed.EmbedField {
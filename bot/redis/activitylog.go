@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+type ActivityEvent string
+
+const (
+	ActivityEventOpen         ActivityEvent = "open"
+	ActivityEventClaim        ActivityEvent = "claim"
+	ActivityEventUnclaim      ActivityEvent = "unclaim"
+	ActivityEventTransfer     ActivityEvent = "transfer"
+	ActivityEventMemberAdd    ActivityEvent = "member_add"
+	ActivityEventMemberRemove ActivityEvent = "member_remove"
+	ActivityEventRename       ActivityEvent = "rename"
+	// Tickets have no priority or status of their own: guilds track these with labels (e.g. "urgent" or "awaiting-reply"),
+	// so priority and status changes are logged as label changes
+	ActivityEventLabel        ActivityEvent = "label"
+	ActivityEventCloseRequest ActivityEvent = "close_request"
+	ActivityEventReopen       ActivityEvent = "reopen"
+	ActivityEventPanelSwitch  ActivityEvent = "panel_switch"
+)
+
+var ActivityEvents = []ActivityEvent{
+	ActivityEventOpen,
+	ActivityEventClaim,
+	ActivityEventUnclaim,
+	ActivityEventTransfer,
+	ActivityEventMemberAdd,
+	ActivityEventMemberRemove,
+	ActivityEventRename,
+	ActivityEventLabel,
+	ActivityEventCloseRequest,
+	ActivityEventReopen,
+	ActivityEventPanelSwitch,
+}
+
+// ActivityLogSettings stores the events that have been turned off, rather than on, so that new event types are logged
+// by default
+type ActivityLogSettings struct {
+	ChannelId      uint64          `json:"channel_id,string"`
+	DisabledEvents []ActivityEvent `json:"disabled_events,omitempty"`
+}
+
+func (s ActivityLogSettings) Enabled() bool {
+	return s.ChannelId != 0
+}
+
+func (s ActivityLogSettings) EventEnabled(event ActivityEvent) bool {
+	if !s.Enabled() {
+		return false
+	}
+
+	for _, disabled := range s.DisabledEvents {
+		if disabled == event {
+			return false
+		}
+	}
+
+	return true
+}
+
+func GetActivityLogSettings(guildId uint64) (ActivityLogSettings, error) {
+	var settings ActivityLogSettings
+
+	res, err := Client.Get(utils.DefaultContext(), buildActivityLogSettingsKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return settings, nil
+		}
+
+		return settings, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &settings); err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+func SetActivityLogSettings(guildId uint64, settings ActivityLogSettings) error {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildActivityLogSettingsKey(guildId), string(encoded), 0).Err()
+}
+
+func buildActivityLogSettingsKey(guildId uint64) string {
+	return fmt.Sprintf("activitylog:%d", guildId)
+}
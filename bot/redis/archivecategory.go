@@ -0,0 +1,137 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ArchiveCategorySettings struct {
+	Enabled            bool    `json:"enabled"`
+	CategoryId         uint64  `json:"category_id"`
+	OverflowCategoryId *uint64 `json:"overflow_category_id,omitempty"`
+	RetentionDays      int     `json:"retention_days"`
+}
+
+type PendingArchiveDeletion struct {
+	GuildId   uint64
+	TicketId  int
+	ChannelId uint64
+}
+
+const pendingArchiveDeletionKey = "archivecategory:pending"
+
+func GetArchiveCategorySettings(guildId uint64) (ArchiveCategorySettings, error) {
+	var settings ArchiveCategorySettings
+
+	res, err := Client.Get(utils.DefaultContext(), buildArchiveCategoryKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return settings, nil
+		}
+
+		return settings, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &settings); err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+func SetArchiveCategorySettings(guildId uint64, settings ArchiveCategorySettings) error {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildArchiveCategoryKey(guildId), string(encoded), 0).Err()
+}
+
+func DeleteArchiveCategorySettings(guildId uint64) error {
+	return Client.Del(utils.DefaultContext(), buildArchiveCategoryKey(guildId)).Err()
+}
+
+func SchedulePendingArchiveDeletion(deletion PendingArchiveDeletion, deleteAt time.Time) error {
+	return Client.ZAdd(utils.DefaultContext(), pendingArchiveDeletionKey, &redis.Z{
+		Score:  float64(deleteAt.Unix()),
+		Member: deletion.encode(),
+	}).Err()
+}
+
+// TakeDuePendingArchiveDeletions removes and returns every deletion that is due. Each entry is only returned to a
+// single worker, as ZREM reports whether this call was the one to remove it.
+func TakeDuePendingArchiveDeletions(now time.Time, limit int64) ([]PendingArchiveDeletion, error) {
+	members, err := Client.ZRangeByScore(utils.DefaultContext(), pendingArchiveDeletionKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: limit,
+	}).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var deletions []PendingArchiveDeletion
+	for _, member := range members {
+		removed, err := Client.ZRem(utils.DefaultContext(), pendingArchiveDeletionKey, member).Result()
+		if err != nil {
+			return deletions, err
+		}
+
+		// Another worker took it first
+		if removed == 0 {
+			continue
+		}
+
+		deletion, err := decodePendingArchiveDeletion(member)
+		if err != nil {
+			return deletions, err
+		}
+
+		deletions = append(deletions, deletion)
+	}
+
+	return deletions, nil
+}
+
+func (d PendingArchiveDeletion) encode() string {
+	return fmt.Sprintf("%d:%d:%d", d.GuildId, d.TicketId, d.ChannelId)
+}
+
+func decodePendingArchiveDeletion(s string) (PendingArchiveDeletion, error) {
+	split := strings.Split(s, ":")
+	if len(split) != 3 {
+		return PendingArchiveDeletion{}, fmt.Errorf("invalid pending archive deletion %s", s)
+	}
+
+	guildId, err := strconv.ParseUint(split[0], 10, 64)
+	if err != nil {
+		return PendingArchiveDeletion{}, err
+	}
+
+	ticketId, err := strconv.Atoi(split[1])
+	if err != nil {
+		return PendingArchiveDeletion{}, err
+	}
+
+	channelId, err := strconv.ParseUint(split[2], 10, 64)
+	if err != nil {
+		return PendingArchiveDeletion{}, err
+	}
+
+	return PendingArchiveDeletion{
+		GuildId:   guildId,
+		TicketId:  ticketId,
+		ChannelId: channelId,
+	}, nil
+}
+
+func buildArchiveCategoryKey(guildId uint64) string {
+	return fmt.Sprintf("archivecategory:%d", guildId)
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	// AuditActionOpenOnBehalf is a staff member opening a ticket for another user, bypassing their ticket limits
+	AuditActionOpenOnBehalf AuditAction = "open_on_behalf"
+)
+
+// How many entries are kept per guild
+const auditLogLength = 1000
+
+type AuditLogEntry struct {
+	Action   AuditAction `json:"action"`
+	ActorId  uint64      `json:"actor_id"`
+	TargetId uint64      `json:"target_id"`
+	TicketId int         `json:"ticket_id"`
+	Time     time.Time   `json:"time"`
+}
+
+func AddAuditLogEntry(guildId uint64, entry AuditLogEntry) error {
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	key := buildAuditLogKey(guildId)
+
+	pipe := Client.TxPipeline()
+	pipe.LPush(utils.DefaultContext(), key, string(encoded))
+	pipe.LTrim(utils.DefaultContext(), key, 0, auditLogLength-1)
+
+	_, err = pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+func buildAuditLogKey(guildId uint64) string {
+	return fmt.Sprintf("auditlog:%d", guildId)
+}
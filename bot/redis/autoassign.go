@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"sync"
+	"time"
+)
+
+type AutoAssignStrategy string
+
+const (
+	AutoAssignRoundRobin  AutoAssignStrategy = "round_robin"
+	AutoAssignLeastClaims AutoAssignStrategy = "least_claims"
+	AutoAssignOnCall      AutoAssignStrategy = "on_call"
+)
+
+var AutoAssignStrategies = []AutoAssignStrategy{AutoAssignRoundRobin, AutoAssignLeastClaims, AutoAssignOnCall}
+
+type AutoAssignSettings struct {
+	Enabled     bool               `json:"enabled"`
+	Strategy    AutoAssignStrategy `json:"strategy"`
+	WorkloadCap int                `json:"workload_cap"` // 0 = no cap
+}
+
+func GetAutoAssignSettings(guildId uint64) (AutoAssignSettings, error) {
+	var settings AutoAssignSettings
+
+	res, err := Client.Get(utils.DefaultContext(), buildAutoAssignKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return settings, nil
+		}
+
+		return settings, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &settings); err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+func SetAutoAssignSettings(guildId uint64, settings AutoAssignSettings) error {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	if err := Client.Set(utils.DefaultContext(), buildAutoAssignKey(guildId), string(encoded), 0).Err(); err != nil {
+		return err
+	}
+
+	cacheAutoAssignEnabled(guildId, settings.Enabled)
+	return nil
+}
+
+func DeleteAutoAssignSettings(guildId uint64) error {
+	if err := Client.Del(utils.DefaultContext(), buildAutoAssignKey(guildId)).Err(); err != nil {
+		return err
+	}
+
+	cacheAutoAssignEnabled(guildId, false)
+	return nil
+}
+
+// Presence updates are the busiest gateway event, and are only needed by guilds using auto-assignment, so whether
+// each guild has it enabled is cached in memory. Other workers may see a change up to this long after it is made.
+const autoAssignEnabledCacheExpiry = time.Minute
+
+type autoAssignEnabledEntry struct {
+	enabled   bool
+	expiresAt time.Time
+}
+
+var (
+	autoAssignEnabledCache   = make(map[uint64]autoAssignEnabledEntry)
+	autoAssignEnabledCacheMu sync.RWMutex
+)
+
+// IsAutoAssignEnabled is a cached version of GetAutoAssignSettings(guildId).Enabled
+func IsAutoAssignEnabled(guildId uint64) (bool, error) {
+	autoAssignEnabledCacheMu.RLock()
+	entry, ok := autoAssignEnabledCache[guildId]
+	autoAssignEnabledCacheMu.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.enabled, nil
+	}
+
+	settings, err := GetAutoAssignSettings(guildId)
+	if err != nil {
+		return false, err
+	}
+
+	cacheAutoAssignEnabled(guildId, settings.Enabled)
+	return settings.Enabled, nil
+}
+
+func cacheAutoAssignEnabled(guildId uint64, enabled bool) {
+	autoAssignEnabledCacheMu.Lock()
+	defer autoAssignEnabledCacheMu.Unlock()
+
+	autoAssignEnabledCache[guildId] = autoAssignEnabledEntry{
+		enabled:   enabled,
+		expiresAt: time.Now().Add(autoAssignEnabledCacheExpiry),
+	}
+}
+
+// NextRoundRobinIndex returns an ever-increasing counter for the guild and panel (0 if no panel is used), to be taken
+// modulo the number of candidates
+func NextRoundRobinIndex(guildId uint64, panelId int) (int64, error) {
+	key := fmt.Sprintf("autoassign:roundrobin:%d:%d", guildId, panelId)
+	return Client.Incr(utils.DefaultContext(), key).Result()
+}
+
+func buildAutoAssignKey(guildId uint64) string {
+	return fmt.Sprintf("autoassign:%d", guildId)
+}
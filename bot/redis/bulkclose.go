@@ -0,0 +1,262 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// BulkCloseFilter describes which open tickets /closeall should close. Nil / false fields are not filtered on.
+type BulkCloseFilter struct {
+	PanelId       *int       `json:"panel_id,omitempty"`
+	InactiveHours *int       `json:"inactive_hours,omitempty"`
+	Unclaimed     bool       `json:"unclaimed"`
+	ClaimedBy     *uint64    `json:"claimed_by,omitempty"`
+	OpenedBefore  *time.Time `json:"opened_before,omitempty"`
+}
+
+type BulkCloseJob struct {
+	GuildId           uint64 `json:"guild_id"`
+	ChannelId         uint64 `json:"channel_id"`
+	UserId            uint64 `json:"user_id"`
+	ProgressMessageId uint64 `json:"progress_message_id"`
+	TicketIds         []int  `json:"ticket_ids"`
+	// Token is the value of the guild's lock while this job holds it. A requeued job is given a new token, so that the
+	// worker it was abandoned by cannot keep running it if it recovers.
+	Token string `json:"token"`
+
+	// The job as it is stored in the processing list, so that it can be removed once finished
+	raw string
+}
+
+const (
+	bulkCloseJobsKey = "bulkclose:jobs"
+	// Jobs stay in the processing list while they run, so that they can be requeued if the worker running them dies
+	bulkCloseProcessingKey = "bulkclose:processing"
+
+	// BulkCloseFilterExpiry is how long the confirmation button remains valid for
+	BulkCloseFilterExpiry = time.Minute * 15
+	// The lock is refreshed as the job runs, so if the worker running it dies, the lock expires soon after, and the job
+	// is requeued by RequeueAbandonedBulkCloseJobs
+	bulkCloseLockExpiry = time.Minute * 5
+)
+
+// Moves a job from the processing list back to the queue, with a new token, unless another worker has already done so
+var requeueBulkCloseJobScript = redis.NewScript(`
+if redis.call("LREM", KEYS[1], 1, ARGV[1]) == 1 then
+	redis.call("RPUSH", KEYS[2], ARGV[2])
+	return 1
+end
+
+return 0
+`)
+
+// Extends the lock if it is held with the token
+var refreshBulkCloseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+
+return 0
+`)
+
+// Takes the lock if it is free, or extends it if it is already held with the token
+var resumeBulkCloseLockScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+elseif current == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+
+return 0
+`)
+
+// Deletes the lock if it is held with the token
+var releaseBulkCloseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+
+return 0
+`)
+
+func StoreBulkCloseFilter(guildId uint64, id string, filter BulkCloseFilter) error {
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildBulkCloseFilterKey(guildId, id), string(encoded), BulkCloseFilterExpiry).Err()
+}
+
+// GetBulkCloseFilter returns false if the filter has expired
+func GetBulkCloseFilter(guildId uint64, id string) (BulkCloseFilter, bool, error) {
+	var filter BulkCloseFilter
+
+	res, err := Client.Get(utils.DefaultContext(), buildBulkCloseFilterKey(guildId, id)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return filter, false, nil
+		}
+
+		return filter, false, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &filter); err != nil {
+		return filter, false, err
+	}
+
+	return filter, true, nil
+}
+
+func DeleteBulkCloseFilter(guildId uint64, id string) error {
+	return Client.Del(utils.DefaultContext(), buildBulkCloseFilterKey(guildId, id)).Err()
+}
+
+// TakeBulkCloseLock ensures only one bulk close job runs per guild at a time. Returns the token the lock is held with,
+// which must be stored in the job, or false if a job is already running.
+func TakeBulkCloseLock(guildId uint64) (string, bool, error) {
+	token, err := newBulkCloseLockToken()
+	if err != nil {
+		return "", false, err
+	}
+
+	ok, err := Client.SetNX(utils.DefaultContext(), buildBulkCloseLockKey(guildId), token, bulkCloseLockExpiry).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	return token, true, nil
+}
+
+// ResumeBulkCloseLock is called before a job starts running. A job that waited in the queue, or was requeued, may have
+// lost its lock, so the lock is taken again if it is free. Returns false if another job now holds the lock.
+func ResumeBulkCloseLock(guildId uint64, token string) (bool, error) {
+	return runBulkCloseLockScript(resumeBulkCloseLockScript, guildId, token, bulkCloseLockExpiry.Milliseconds())
+}
+
+// RefreshBulkCloseLock extends the lock while the guild's job is running. Returns false if the lock has expired, or is
+// held by another job, in which case the job must stop.
+func RefreshBulkCloseLock(guildId uint64, token string) (bool, error) {
+	return runBulkCloseLockScript(refreshBulkCloseLockScript, guildId, token, bulkCloseLockExpiry.Milliseconds())
+}
+
+// ReleaseBulkCloseLock releases the lock, unless it is now held by another job
+func ReleaseBulkCloseLock(guildId uint64, token string) error {
+	_, err := runBulkCloseLockScript(releaseBulkCloseLockScript, guildId, token)
+	return err
+}
+
+func runBulkCloseLockScript(script *redis.Script, guildId uint64, token string, args ...interface{}) (bool, error) {
+	res, err := script.Run(utils.DefaultContext(), Client, []string{buildBulkCloseLockKey(guildId)}, append([]interface{}{token}, args...)...).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return res == 1, nil
+}
+
+func newBulkCloseLockToken() (string, error) {
+	token, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	return token.String(), nil
+}
+
+func QueueBulkCloseJob(job BulkCloseJob) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	// Jobs are taken from the right
+	return Client.LPush(utils.DefaultContext(), bulkCloseJobsKey, string(encoded)).Err()
+}
+
+// TakeBulkCloseJob blocks until a job is available, so cannot use the default context timeout. The job is kept in the
+// processing list until CompleteBulkCloseJob is called.
+func TakeBulkCloseJob() (BulkCloseJob, error) {
+	var job BulkCloseJob
+
+	res, err := Client.BRPopLPush(context.Background(), bulkCloseJobsKey, bulkCloseProcessingKey, 0).Result()
+	if err != nil {
+		return job, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &job); err != nil {
+		// Would otherwise be requeued forever
+		_ = Client.LRem(utils.DefaultContext(), bulkCloseProcessingKey, 1, res).Err()
+		return job, err
+	}
+
+	job.raw = res
+	return job, nil
+}
+
+// CompleteBulkCloseJob removes the job from the processing list, once it has finished or been abandoned
+func CompleteBulkCloseJob(job BulkCloseJob) error {
+	return Client.LRem(utils.DefaultContext(), bulkCloseProcessingKey, 1, job.raw).Err()
+}
+
+// RequeueAbandonedBulkCloseJobs puts jobs whose lock has expired, as the worker running them died, back in the queue.
+// Returns the number of jobs requeued.
+func RequeueAbandonedBulkCloseJobs() (int, error) {
+	res, err := Client.LRange(utils.DefaultContext(), bulkCloseProcessingKey, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var requeued int
+	for _, raw := range res {
+		var job BulkCloseJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			return requeued, err
+		}
+
+		job.Token, err = newBulkCloseLockToken()
+		if err != nil {
+			return requeued, err
+		}
+
+		encoded, err := json.Marshal(job)
+		if err != nil {
+			return requeued, err
+		}
+
+		locked, err := Client.Exists(utils.DefaultContext(), buildBulkCloseLockKey(job.GuildId)).Result()
+		if err != nil {
+			return requeued, err
+		}
+
+		if locked > 0 {
+			continue
+		}
+
+		moved, err := requeueBulkCloseJobScript.Run(utils.DefaultContext(), Client, []string{bulkCloseProcessingKey, bulkCloseJobsKey}, raw, string(encoded)).Int()
+		if err != nil {
+			return requeued, err
+		}
+
+		requeued += moved
+	}
+
+	return requeued, nil
+}
+
+func buildBulkCloseFilterKey(guildId uint64, id string) string {
+	return fmt.Sprintf("bulkclose:filter:%d:%s", guildId, id)
+}
+
+func buildBulkCloseLockKey(guildId uint64) string {
+	return fmt.Sprintf("bulkclose:lock:%d", guildId)
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+type BusinessHoursMode string
+
+const (
+	// BusinessHoursModeRefuse prevents tickets from being opened outside of business hours
+	BusinessHoursModeRefuse BusinessHoursMode = "refuse"
+	// BusinessHoursModeNotice opens the ticket, but tells the user when they can expect a response
+	BusinessHoursModeNotice BusinessHoursMode = "notice"
+)
+
+var BusinessHoursModes = []BusinessHoursMode{BusinessHoursModeRefuse, BusinessHoursModeNotice}
+
+// BusinessHoursPeriod is a range of minutes since midnight, in the schedule's time zone
+type BusinessHoursPeriod struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type BusinessHours struct {
+	Timezone string `json:"timezone"`
+	// Indexed by time.Weekday
+	Days     [7][]BusinessHoursPeriod `json:"days"`
+	Holidays []string                 `json:"holidays"` // YYYY-MM-DD, in the schedule's time zone
+	Mode     BusinessHoursMode        `json:"mode"`
+	Message  *string                  `json:"message,omitempty"` // Replaces the default refusal message
+}
+
+// GetBusinessHours returns the schedule for the panel if it has one, otherwise the guild-wide schedule. A nil panel ID
+// only checks the guild-wide schedule. Returns false if there is no schedule, in which case support is always open.
+func GetBusinessHours(guildId uint64, panelId *int) (BusinessHours, bool, error) {
+	if panelId != nil {
+		hours, ok, err := getBusinessHours(buildPanelBusinessHoursKey(guildId, *panelId))
+		if err != nil || ok {
+			return hours, ok, err
+		}
+	}
+
+	return getBusinessHours(buildGuildBusinessHoursKey(guildId))
+}
+
+func SetBusinessHours(guildId uint64, panelId *int, hours BusinessHours) error {
+	encoded, err := json.Marshal(hours)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildBusinessHoursKey(guildId, panelId), string(encoded), 0).Err()
+}
+
+func DeleteBusinessHours(guildId uint64, panelId *int) error {
+	return Client.Del(utils.DefaultContext(), buildBusinessHoursKey(guildId, panelId)).Err()
+}
+
+func getBusinessHours(key string) (BusinessHours, bool, error) {
+	var hours BusinessHours
+
+	res, err := Client.Get(utils.DefaultContext(), key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return hours, false, nil
+		}
+
+		return hours, false, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &hours); err != nil {
+		return hours, false, err
+	}
+
+	return hours, true, nil
+}
+
+func buildBusinessHoursKey(guildId uint64, panelId *int) string {
+	if panelId == nil {
+		return buildGuildBusinessHoursKey(guildId)
+	} else {
+		return buildPanelBusinessHoursKey(guildId, *panelId)
+	}
+}
+
+func buildGuildBusinessHoursKey(guildId uint64) string {
+	return fmt.Sprintf("businesshours:%d", guildId)
+}
+
+func buildPanelBusinessHoursKey(guildId uint64, panelId int) string {
+	return fmt.Sprintf("businesshours:%d:panel:%d", guildId, panelId)
+}
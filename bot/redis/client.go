@@ -1,3 +1,7 @@
+// Package redis holds the worker's caches and ratelimits, as well as settings and state for features whose schema is
+// not part of the database module, such as labels, business hours and the transcript outbox. The Postgres schema is
+// owned by the database module, and the worker does not create tables of its own, so these keys are stored without an
+// expiry: Redis must be run with persistence (AOF or RDB snapshots) enabled, or this data is lost on restart.
 package redis
 
 import (
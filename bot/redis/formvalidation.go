@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+type FormInputFormat string
+
+const (
+	FormInputFormatText      FormInputFormat = "text"
+	FormInputFormatNumber    FormInputFormat = "number"
+	FormInputFormatEmail     FormInputFormat = "email"
+	FormInputFormatUrl       FormInputFormat = "url"
+	FormInputFormatDiscordId FormInputFormat = "discord_id"
+)
+
+var FormInputFormats = []FormInputFormat{
+	FormInputFormatText,
+	FormInputFormatNumber,
+	FormInputFormatEmail,
+	FormInputFormatUrl,
+	FormInputFormatDiscordId,
+}
+
+type FormInputValidation struct {
+	MinLength *int            `json:"min_length,omitempty"`
+	MaxLength *int            `json:"max_length,omitempty"`
+	Pattern   *string         `json:"pattern,omitempty"`
+	Format    FormInputFormat `json:"format"`
+	// Only used with FormInputFormatNumber
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// How long a user has to complete the next page of a form, or correct their answers, before they must start again
+const formAnswersExpiry = time.Minute * 30
+
+// GetFormValidation returns the validation rules for the inputs of a form, keyed by input ID
+func GetFormValidation(formId int) (map[int]FormInputValidation, error) {
+	res, err := Client.HGetAll(utils.DefaultContext(), buildFormValidationKey(formId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	validation := make(map[int]FormInputValidation)
+	for rawInputId, encoded := range res {
+		inputId, err := strconv.Atoi(rawInputId)
+		if err != nil {
+			return nil, err
+		}
+
+		var rules FormInputValidation
+		if err := json.Unmarshal([]byte(encoded), &rules); err != nil {
+			return nil, err
+		}
+
+		validation[inputId] = rules
+	}
+
+	return validation, nil
+}
+
+func SetFormInputValidation(formId, inputId int, validation FormInputValidation) error {
+	encoded, err := json.Marshal(validation)
+	if err != nil {
+		return err
+	}
+
+	return Client.HSet(utils.DefaultContext(), buildFormValidationKey(formId), strconv.Itoa(inputId), string(encoded)).Err()
+}
+
+func DeleteFormInputValidation(formId, inputId int) error {
+	return Client.HDel(utils.DefaultContext(), buildFormValidationKey(formId), strconv.Itoa(inputId)).Err()
+}
+
+// StoreFormAnswers stores a user's answers to the pages of a form they have submitted so far, keyed by input custom ID.
+// Rejected answers are also stored, so the form can be prefilled when they try again.
+func StoreFormAnswers(guildId, userId uint64, panelId int, answers map[string]string) error {
+	encoded, err := json.Marshal(answers)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildFormAnswersKey(guildId, userId, panelId), string(encoded), formAnswersExpiry).Err()
+}
+
+// GetFormAnswers returns nil if there are no stored answers
+func GetFormAnswers(guildId, userId uint64, panelId int) (map[string]string, error) {
+	res, err := Client.Get(utils.DefaultContext(), buildFormAnswersKey(guildId, userId, panelId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var answers map[string]string
+	if err := json.Unmarshal([]byte(res), &answers); err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+func DeleteFormAnswers(guildId, userId uint64, panelId int) error {
+	return Client.Del(utils.DefaultContext(), buildFormAnswersKey(guildId, userId, panelId)).Err()
+}
+
+func buildFormValidationKey(formId int) string {
+	return fmt.Sprintf("formvalidation:%d", formId)
+}
+
+func buildFormAnswersKey(guildId, userId uint64, panelId int) string {
+	return fmt.Sprintf("formanswers:%d:%d:%d", guildId, userId, panelId)
+}
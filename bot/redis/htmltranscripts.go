@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+type HtmlTranscriptSettings struct {
+	// Attach the transcript to the close log in the archive channel
+	ArchiveChannel bool `json:"archive_channel"`
+	// Attach the transcript to the close message sent to the ticket opener
+	DirectMessage bool `json:"direct_message"`
+}
+
+func (s HtmlTranscriptSettings) Enabled() bool {
+	return s.ArchiveChannel || s.DirectMessage
+}
+
+func GetHtmlTranscriptSettings(guildId uint64) (HtmlTranscriptSettings, error) {
+	var settings HtmlTranscriptSettings
+
+	res, err := Client.Get(utils.DefaultContext(), buildHtmlTranscriptSettingsKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return settings, nil
+		}
+
+		return settings, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &settings); err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+func SetHtmlTranscriptSettings(guildId uint64, settings HtmlTranscriptSettings) error {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildHtmlTranscriptSettingsKey(guildId), string(encoded), 0).Err()
+}
+
+func DeleteHtmlTranscriptSettings(guildId uint64) error {
+	return Client.Del(utils.DefaultContext(), buildHtmlTranscriptSettingsKey(guildId)).Err()
+}
+
+func buildHtmlTranscriptSettingsKey(guildId uint64) string {
+	return fmt.Sprintf("htmltranscripts:%d", guildId)
+}
@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+const (
+	MaxLabelLength = 32
+	MaxGuildLabels = 50
+)
+
+// Adds the labels to the guild's set, unless doing so would take it past the limit. Returns 1 if the labels were added.
+var createGuildLabelsScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local count = redis.call("SCARD", KEYS[1])
+
+for i = 2, #ARGV do
+	if redis.call("SISMEMBER", KEYS[1], ARGV[i]) == 0 then
+		count = count + 1
+	end
+end
+
+if count > limit then
+	return 0
+end
+
+for i = 2, #ARGV do
+	redis.call("SADD", KEYS[1], ARGV[i])
+end
+
+return 1
+`)
+
+func GetGuildLabels(guildId uint64) ([]string, error) {
+	return Client.SMembers(utils.DefaultContext(), buildGuildLabelsKey(guildId)).Result()
+}
+
+func IsGuildLabel(guildId uint64, label string) (bool, error) {
+	return Client.SIsMember(utils.DefaultContext(), buildGuildLabelsKey(guildId), label).Result()
+}
+
+// CreateGuildLabels adds the labels to the guild. Returns false, without creating any of them, if the guild would end up
+// with more than MaxGuildLabels labels.
+func CreateGuildLabels(guildId uint64, labels ...string) (bool, error) {
+	if len(labels) == 0 {
+		return true, nil
+	}
+
+	args := make([]interface{}, len(labels)+1)
+	args[0] = MaxGuildLabels
+	for i, label := range labels {
+		args[i+1] = label
+	}
+
+	res, err := createGuildLabelsScript.Run(utils.DefaultContext(), Client, []string{buildGuildLabelsKey(guildId)}, args...).Result()
+	if err != nil {
+		return false, err
+	}
+
+	created, ok := res.(int64)
+	if !ok {
+		return false, fmt.Errorf("create guild labels returned %v, not an int64", res)
+	}
+
+	return created == 1, nil
+}
+
+// DeleteGuildLabel removes the label from the guild, and from every ticket and panel it was applied to
+func DeleteGuildLabel(guildId uint64, label string, panelIds []int) error {
+	ticketIds, err := GetLabelTicketIds(guildId, label)
+	if err != nil {
+		return err
+	}
+
+	pipe := Client.TxPipeline()
+	pipe.SRem(utils.DefaultContext(), buildGuildLabelsKey(guildId), label)
+	pipe.Del(utils.DefaultContext(), buildLabelTicketsKey(guildId, label))
+	for _, ticketId := range ticketIds {
+		pipe.SRem(utils.DefaultContext(), buildTicketLabelsKey(guildId, ticketId), label)
+	}
+
+	for _, panelId := range panelIds {
+		pipe.SRem(utils.DefaultContext(), buildPanelLabelsKey(guildId, panelId), label)
+	}
+
+	_, err = pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+func GetTicketLabels(guildId uint64, ticketId int) ([]string, error) {
+	return Client.SMembers(utils.DefaultContext(), buildTicketLabelsKey(guildId, ticketId)).Result()
+}
+
+// AddTicketLabels applies the labels to the ticket. The labels must already exist, see CreateGuildLabels.
+func AddTicketLabels(guildId uint64, ticketId int, labels ...string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	pipe := Client.TxPipeline()
+	for _, label := range labels {
+		pipe.SAdd(utils.DefaultContext(), buildTicketLabelsKey(guildId, ticketId), label)
+		pipe.SAdd(utils.DefaultContext(), buildLabelTicketsKey(guildId, label), ticketId)
+	}
+
+	_, err := pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+// RemoveTicketLabel returns false if the ticket did not have the label
+func RemoveTicketLabel(guildId uint64, ticketId int, label string) (bool, error) {
+	pipe := Client.TxPipeline()
+	removed := pipe.SRem(utils.DefaultContext(), buildTicketLabelsKey(guildId, ticketId), label)
+	pipe.SRem(utils.DefaultContext(), buildLabelTicketsKey(guildId, label), ticketId)
+
+	if _, err := pipe.Exec(utils.DefaultContext()); err != nil {
+		return false, err
+	}
+
+	return removed.Val() > 0, nil
+}
+
+func GetLabelTicketIds(guildId uint64, label string) ([]int, error) {
+	res, err := Client.SMembers(utils.DefaultContext(), buildLabelTicketsKey(guildId, label)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ticketIds := make([]int, len(res))
+	for i, raw := range res {
+		ticketIds[i], err = strconv.Atoi(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ticketIds, nil
+}
+
+// GetPanelLabels returns the labels applied to tickets opened from the panel
+func GetPanelLabels(guildId uint64, panelId int) ([]string, error) {
+	return Client.SMembers(utils.DefaultContext(), buildPanelLabelsKey(guildId, panelId)).Result()
+}
+
+// SetPanelLabels replaces the panel's labels, creating any that the guild does not have yet. Returns false, leaving the
+// panel unchanged, if this would take the guild past MaxGuildLabels.
+func SetPanelLabels(guildId uint64, panelId int, labels []string) (bool, error) {
+	created, err := CreateGuildLabels(guildId, labels...)
+	if err != nil || !created {
+		return false, err
+	}
+
+	key := buildPanelLabelsKey(guildId, panelId)
+
+	pipe := Client.TxPipeline()
+	pipe.Del(utils.DefaultContext(), key)
+	for _, label := range labels {
+		pipe.SAdd(utils.DefaultContext(), key, label)
+	}
+
+	_, err = pipe.Exec(utils.DefaultContext())
+	return true, err
+}
+
+func buildGuildLabelsKey(guildId uint64) string {
+	return fmt.Sprintf("labels:%d", guildId)
+}
+
+func buildTicketLabelsKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("labels:%d:ticket:%d", guildId, ticketId)
+}
+
+func buildLabelTicketsKey(guildId uint64, label string) string {
+	return fmt.Sprintf("labels:%d:label:%s", guildId, label)
+}
+
+func buildPanelLabelsKey(guildId uint64, panelId int) string {
+	return fmt.Sprintf("labels:%d:panel:%d", guildId, panelId)
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+// GetMessageLogChannel returns the channel that edits and deletions in ticket channels are logged to, or nil if the
+// guild has not set one
+func GetMessageLogChannel(guildId uint64) (*uint64, error) {
+	res, err := Client.Get(utils.DefaultContext(), buildMessageLogKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	channelId, err := strconv.ParseUint(res, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &channelId, nil
+}
+
+func SetMessageLogChannel(guildId, channelId uint64) error {
+	return Client.Set(utils.DefaultContext(), buildMessageLogKey(guildId), channelId, 0).Err()
+}
+
+func DeleteMessageLogChannel(guildId uint64) error {
+	return Client.Del(utils.DefaultContext(), buildMessageLogKey(guildId)).Err()
+}
+
+func buildMessageLogKey(guildId uint64) string {
+	return fmt.Sprintf("messagelog:%d", guildId)
+}
@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// PanelRequirements must be met by a user before a panel will open a ticket for them
+type PanelRequirements struct {
+	RequiredRoles     []uint64 `json:"required_roles"` // The user must have at least one
+	ForbiddenRoles    []uint64 `json:"forbidden_roles"`
+	MinAccountAgeDays int      `json:"min_account_age_days"`
+	MinMemberDays     int      `json:"min_member_days"`
+	RequireBloxlink   bool     `json:"require_bloxlink"`
+}
+
+// GetPanelRequirements returns false if the panel has no requirements
+func GetPanelRequirements(guildId uint64, panelId int) (PanelRequirements, bool, error) {
+	var requirements PanelRequirements
+
+	res, err := Client.Get(utils.DefaultContext(), buildPanelRequirementsKey(guildId, panelId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return requirements, false, nil
+		}
+
+		return requirements, false, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &requirements); err != nil {
+		return requirements, false, err
+	}
+
+	return requirements, true, nil
+}
+
+func SetPanelRequirements(guildId uint64, panelId int, requirements PanelRequirements) error {
+	encoded, err := json.Marshal(requirements)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildPanelRequirementsKey(guildId, panelId), string(encoded), 0).Err()
+}
+
+func DeletePanelRequirements(guildId uint64, panelId int) error {
+	return Client.Del(utils.DefaultContext(), buildPanelRequirementsKey(guildId, panelId)).Err()
+}
+
+func buildPanelRequirementsKey(guildId uint64, panelId int) string {
+	return fmt.Sprintf("panelrequirements:%d:%d", guildId, panelId)
+}
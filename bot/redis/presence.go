@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"time"
+)
+
+// Presence updates are not replayed, so a member who goes offline and is never seen again should not stay offline
+// forever. Members without a recorded status are assumed to be online.
+const offlineExpiry = time.Hour * 24
+
+// SetMemberOffline records whether the member was offline in their most recent presence update
+func SetMemberOffline(guildId, userId uint64, offline bool) error {
+	key := buildMemberOfflineKey(guildId, userId)
+
+	if offline {
+		return Client.Set(utils.DefaultContext(), key, 1, offlineExpiry).Err()
+	} else {
+		return Client.Del(utils.DefaultContext(), key).Err()
+	}
+}
+
+// GetOfflineMembers returns the subset of the users who are known to be offline
+func GetOfflineMembers(guildId uint64, userIds []uint64) (map[uint64]bool, error) {
+	offline := make(map[uint64]bool)
+	if len(userIds) == 0 {
+		return offline, nil
+	}
+
+	keys := make([]string, len(userIds))
+	for i, userId := range userIds {
+		keys[i] = buildMemberOfflineKey(guildId, userId)
+	}
+
+	res, err := Client.MGet(utils.DefaultContext(), keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range res {
+		if value != nil {
+			offline[userIds[i]] = true
+		}
+	}
+
+	return offline, nil
+}
+
+func buildMemberOfflineKey(guildId, userId uint64) string {
+	return fmt.Sprintf("presence:offline:%d:%d", guildId, userId)
+}
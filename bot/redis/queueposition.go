@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+// Discord only accepts follow up messages for 15 minutes after an interaction is received
+const queueInteractionTokenExpiry = time.Minute * 14
+
+// GetQueuePositions returns the last position each ticket was told it was at in the queue, keyed by ticket ID
+func GetQueuePositions(guildId uint64) (map[int]int, error) {
+	res, err := Client.HGetAll(utils.DefaultContext(), buildQueuePositionsKey(guildId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[int]int)
+	for rawTicketId, rawPosition := range res {
+		ticketId, err := strconv.Atoi(rawTicketId)
+		if err != nil {
+			return nil, err
+		}
+
+		position, err := strconv.Atoi(rawPosition)
+		if err != nil {
+			return nil, err
+		}
+
+		positions[ticketId] = position
+	}
+
+	return positions, nil
+}
+
+func SetQueuePosition(guildId uint64, ticketId, position int) error {
+	return Client.HSet(utils.DefaultContext(), buildQueuePositionsKey(guildId), strconv.Itoa(ticketId), position).Err()
+}
+
+func DeleteQueuePosition(guildId uint64, ticketId int) error {
+	pipe := Client.TxPipeline()
+	pipe.HDel(utils.DefaultContext(), buildQueuePositionsKey(guildId), strconv.Itoa(ticketId))
+	pipe.Del(utils.DefaultContext(), buildQueueInteractionTokenKey(guildId, ticketId))
+
+	_, err := pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+// SetQueueInteractionToken stores the token of the interaction that opened the ticket, so the opener can be sent
+// ephemeral updates about their position in the queue
+func SetQueueInteractionToken(guildId uint64, ticketId int, token string) error {
+	return Client.Set(utils.DefaultContext(), buildQueueInteractionTokenKey(guildId, ticketId), token, queueInteractionTokenExpiry).Err()
+}
+
+// GetQueueInteractionToken returns nil if the token has expired
+func GetQueueInteractionToken(guildId uint64, ticketId int) (*string, error) {
+	token, err := Client.Get(utils.DefaultContext(), buildQueueInteractionTokenKey(guildId, ticketId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func buildQueuePositionsKey(guildId uint64) string {
+	return fmt.Sprintf("queuepositions:%d", guildId)
+}
+
+func buildQueueInteractionTokenKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("queuepositions:%d:token:%d", guildId, ticketId)
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+type RedactionDetector string
+
+const (
+	RedactionDetectorEmail RedactionDetector = "email"
+	RedactionDetectorPhone RedactionDetector = "phone"
+	RedactionDetectorCard  RedactionDetector = "card"
+	RedactionDetectorToken RedactionDetector = "token"
+	RedactionDetectorIp    RedactionDetector = "ip"
+)
+
+// RedactionDetectors are applied in this order, so that more specific patterns take precedence, e.g. card numbers are
+// not matched as phone numbers
+var RedactionDetectors = []RedactionDetector{
+	RedactionDetectorToken,
+	RedactionDetectorCard,
+	RedactionDetectorEmail,
+	RedactionDetectorIp,
+	RedactionDetectorPhone,
+}
+
+// Custom rules are applied to every message in the transcript, so are limited
+const MaxRedactionRules = 10
+
+type RedactionRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+type RedactionSettings struct {
+	Detectors   []RedactionDetector `json:"detectors,omitempty"`
+	CustomRules []RedactionRule     `json:"custom_rules,omitempty"`
+}
+
+func (s RedactionSettings) Enabled() bool {
+	return len(s.Detectors) > 0 || len(s.CustomRules) > 0
+}
+
+func GetRedactionSettings(guildId uint64) (RedactionSettings, error) {
+	var settings RedactionSettings
+
+	res, err := Client.Get(utils.DefaultContext(), buildRedactionSettingsKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return settings, nil
+		}
+
+		return settings, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &settings); err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+// SetRedactionSettings removes the settings if no rules are enabled
+func SetRedactionSettings(guildId uint64, settings RedactionSettings) error {
+	if !settings.Enabled() {
+		return Client.Del(utils.DefaultContext(), buildRedactionSettingsKey(guildId)).Err()
+	}
+
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildRedactionSettingsKey(guildId), string(encoded), 0).Err()
+}
+
+func buildRedactionSettingsKey(guildId uint64) string {
+	return fmt.Sprintf("redaction:%d", guildId)
+}
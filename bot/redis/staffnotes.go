@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+type StaffNotesMode string
+
+const (
+	StaffNotesModeThread  StaffNotesMode = "thread"
+	StaffNotesModeChannel StaffNotesMode = "channel"
+)
+
+var StaffNotesModes = []StaffNotesMode{
+	StaffNotesModeThread,
+	StaffNotesModeChannel,
+}
+
+type StaffNotesSettings struct {
+	Enabled bool           `json:"enabled"`
+	Mode    StaffNotesMode `json:"mode"`
+	// Only used with StaffNotesModeChannel. If nil, notes channels are created in the same category as the ticket.
+	CategoryId *uint64 `json:"category_id,omitempty"`
+}
+
+func GetStaffNotesSettings(guildId uint64) (StaffNotesSettings, error) {
+	var settings StaffNotesSettings
+
+	res, err := Client.Get(utils.DefaultContext(), buildStaffNotesSettingsKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return settings, nil
+		}
+
+		return settings, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &settings); err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+func SetStaffNotesSettings(guildId uint64, settings StaffNotesSettings) error {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildStaffNotesSettingsKey(guildId), string(encoded), 0).Err()
+}
+
+func DeleteStaffNotesSettings(guildId uint64) error {
+	return Client.Del(utils.DefaultContext(), buildStaffNotesSettingsKey(guildId)).Err()
+}
+
+// GetStaffNotesChannel returns the thread or channel that the ticket's staff notes are posted in, or nil if it does
+// not have one
+func GetStaffNotesChannel(guildId uint64, ticketId int) (*uint64, error) {
+	res, err := Client.HGet(utils.DefaultContext(), buildStaffNotesChannelsKey(guildId), strconv.Itoa(ticketId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	channelId, err := strconv.ParseUint(res, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &channelId, nil
+}
+
+func SetStaffNotesChannel(guildId uint64, ticketId int, channelId uint64) error {
+	return Client.HSet(utils.DefaultContext(), buildStaffNotesChannelsKey(guildId), strconv.Itoa(ticketId), channelId).Err()
+}
+
+func DeleteStaffNotesChannel(guildId uint64, ticketId int) error {
+	return Client.HDel(utils.DefaultContext(), buildStaffNotesChannelsKey(guildId), strconv.Itoa(ticketId)).Err()
+}
+
+func buildStaffNotesSettingsKey(guildId uint64) string {
+	return fmt.Sprintf("staffnotes:%d", guildId)
+}
+
+func buildStaffNotesChannelsKey(guildId uint64) string {
+	return fmt.Sprintf("staffnotes:%d:channels", guildId)
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"time"
+)
+
+// Answers are only needed for placeholders, so are not kept forever
+const ticketFormAnswersExpiry = time.Hour * 24 * 90
+
+// SetTicketFormAnswers stores the form answers a ticket was opened with, keyed by placeholder name
+func SetTicketFormAnswers(guildId uint64, ticketId int, answers map[string]string) error {
+	if len(answers) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(answers))
+	for name, answer := range answers {
+		values[name] = answer
+	}
+
+	key := buildTicketFormAnswersKey(guildId, ticketId)
+
+	pipe := Client.TxPipeline()
+	pipe.HSet(utils.DefaultContext(), key, values)
+	pipe.Expire(utils.DefaultContext(), key, ticketFormAnswersExpiry)
+
+	_, err := pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+// GetTicketFormAnswers returns an empty map if the ticket was not opened with a form
+func GetTicketFormAnswers(guildId uint64, ticketId int) (map[string]string, error) {
+	return Client.HGetAll(utils.DefaultContext(), buildTicketFormAnswersKey(guildId, ticketId)).Result()
+}
+
+func buildTicketFormAnswersKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("ticketformanswers:%d:%d", guildId, ticketId)
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// TicketLimits are applied on top of the guild-wide ticket limit
+type TicketLimits struct {
+	// Maximum open tickets per user from each panel
+	PanelLimits map[int]int `json:"panel_limits,omitempty"`
+	// Replaces the guild-wide limit for users with the role. If a user has multiple, the highest applies.
+	RoleLimits map[uint64]int `json:"role_limits,omitempty"`
+	// Link users to their existing ticket instead of opening another from the same panel
+	RedirectDuplicates bool `json:"redirect_duplicates"`
+}
+
+func GetTicketLimits(guildId uint64) (TicketLimits, error) {
+	var limits TicketLimits
+
+	res, err := Client.Get(utils.DefaultContext(), buildTicketLimitsKey(guildId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return limits, nil
+		}
+
+		return limits, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &limits); err != nil {
+		return limits, err
+	}
+
+	return limits, nil
+}
+
+func SetTicketLimits(guildId uint64, limits TicketLimits) error {
+	encoded, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildTicketLimitsKey(guildId), string(encoded), 0).Err()
+}
+
+func buildTicketLimitsKey(guildId uint64) string {
+	return fmt.Sprintf("ticketlimits:%d", guildId)
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// TicketListFilter describes which tickets /tickets list and /tickets search should show. Nil fields are not filtered on.
+type TicketListFilter struct {
+	UserId    *uint64    `json:"user_id,omitempty"`
+	Open      *bool      `json:"open,omitempty"`
+	PanelId   *int       `json:"panel_id,omitempty"`
+	ClaimedBy *uint64    `json:"claimed_by,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+}
+
+// TicketListFilterExpiry is how long the page buttons remain usable for
+const TicketListFilterExpiry = time.Minute * 30
+
+func StoreTicketListFilter(guildId uint64, id string, filter TicketListFilter) error {
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildTicketListFilterKey(guildId, id), string(encoded), TicketListFilterExpiry).Err()
+}
+
+// GetTicketListFilter returns false if the filter has expired
+func GetTicketListFilter(guildId uint64, id string) (TicketListFilter, bool, error) {
+	var filter TicketListFilter
+
+	res, err := Client.Get(utils.DefaultContext(), buildTicketListFilterKey(guildId, id)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return filter, false, nil
+		}
+
+		return filter, false, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &filter); err != nil {
+		return filter, false, err
+	}
+
+	return filter, true, nil
+}
+
+func buildTicketListFilterKey(guildId uint64, id string) string {
+	return fmt.Sprintf("ticketlist:filter:%d:%s", guildId, id)
+}
@@ -0,0 +1,262 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/objects/user"
+	"strconv"
+	"time"
+)
+
+type TranscriptLogEventType string
+
+const (
+	// TranscriptLogEventStart is always the first event in a log. If it is missing, messages were sent before the log
+	// was started, and the log cannot be used to build the transcript.
+	TranscriptLogEventStart  TranscriptLogEventType = "start"
+	TranscriptLogEventCreate TranscriptLogEventType = "create"
+	TranscriptLogEventUpdate TranscriptLogEventType = "update"
+	TranscriptLogEventDelete TranscriptLogEventType = "delete"
+)
+
+type TranscriptLogEvent struct {
+	Type      TranscriptLogEventType `json:"type"`
+	MessageId uint64                 `json:"message_id,string,omitempty"`
+	// Only set for create and update events
+	Message *message.Message `json:"message,omitempty"`
+	// Only set for delete events, from the audit log. DeletedByTarget is the author of the messages the audit log entry
+	// is for, as entries for other messages in the same channel may be picked up.
+	DeletedBy       *user.User `json:"deleted_by,omitempty"`
+	DeletedByTarget uint64     `json:"deleted_by_target,string,omitempty"`
+	Time            time.Time  `json:"time"`
+}
+
+// Logs are kept while the ticket is open, and removed once the transcript has been stored
+const transcriptLogExpiry = time.Hour * 24 * 90
+
+// Updates are merged into the latest version of the message optimistically, and retried if another event for the ticket
+// is logged at the same time
+const transcriptLogUpdateAttempts = 5
+
+// Appends the event to the log, and stores the latest version of the message, if any, in the hash alongside it. Does
+// nothing if the log was never started. If ARGV[5] is 1, the message is only stored if it is not already.
+var appendTranscriptLogScript = redis.NewScript(`
+if redis.call("RPUSHX", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+
+if ARGV[4] ~= "" then
+	if ARGV[5] == "1" then
+		redis.call("HSETNX", KEYS[2], ARGV[3], ARGV[4])
+	else
+		redis.call("HSET", KEYS[2], ARGV[3], ARGV[4])
+	end
+
+	redis.call("EXPIRE", KEYS[2], ARGV[2])
+end
+
+return 1
+`)
+
+// StartTranscriptLog must be called before any messages are sent in a new ticket
+func StartTranscriptLog(guildId uint64, ticketId int) error {
+	event := TranscriptLogEvent{
+		Type: TranscriptLogEventStart,
+		Time: time.Now(),
+	}
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	key := buildTranscriptLogKey(guildId, ticketId)
+
+	pipe := Client.TxPipeline()
+	pipe.Del(utils.DefaultContext(), key, buildTranscriptLogMessagesKey(guildId, ticketId))
+	pipe.RPush(utils.DefaultContext(), key, string(encoded))
+	pipe.Expire(utils.DefaultContext(), key, transcriptLogExpiry)
+
+	_, err = pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+// AppendTranscriptLog does nothing if the log was never started, e.g. if the ticket was opened before logs existed. The
+// latest version of each message is also kept alongside the log, so that GetLoggedMessage does not have to read
+// the whole log.
+func AppendTranscriptLog(guildId uint64, ticketId int, event TranscriptLogEvent) error {
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if event.Type == TranscriptLogEventUpdate && event.Message != nil {
+		return appendTranscriptLogUpdate(guildId, ticketId, event, string(encoded))
+	}
+
+	// The welcome message is logged when sent, as well as by the listener, so the first version is kept
+	var latest string
+	if event.Type == TranscriptLogEventCreate && event.Message != nil {
+		encodedMessage, err := json.Marshal(event.Message)
+		if err != nil {
+			return err
+		}
+
+		latest = string(encodedMessage)
+	}
+
+	return appendTranscriptLogScript.Run(
+		utils.DefaultContext(),
+		Client,
+		[]string{buildTranscriptLogKey(guildId, ticketId), buildTranscriptLogMessagesKey(guildId, ticketId)},
+		string(encoded), int(transcriptLogExpiry.Seconds()), event.MessageId, latest, "1",
+	).Err()
+}
+
+func appendTranscriptLogUpdate(guildId uint64, ticketId int, event TranscriptLogEvent, encoded string) error {
+	keys := []string{buildTranscriptLogKey(guildId, ticketId), buildTranscriptLogMessagesKey(guildId, ticketId)}
+	field := strconv.FormatUint(event.MessageId, 10)
+
+	update := func(tx *redis.Tx) error {
+		// Messages that were never logged are not stored, as the update may not contain the whole message
+		var latest string
+		res, err := tx.HGet(utils.DefaultContext(), keys[1], field).Result()
+		if err == nil {
+			var msg message.Message
+			if err := json.Unmarshal([]byte(res), &msg); err != nil {
+				return err
+			}
+
+			ApplyMessageUpdate(&msg, *event.Message)
+
+			encodedMessage, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+
+			latest = string(encodedMessage)
+		} else if err != redis.Nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(utils.DefaultContext(), func(pipe redis.Pipeliner) error {
+			// Eval rather than Run, as the script cannot be loaded on demand inside a transaction
+			appendTranscriptLogScript.Eval(utils.DefaultContext(), pipe, keys, encoded, int(transcriptLogExpiry.Seconds()), field, latest, "0")
+			return nil
+		})
+
+		return err
+	}
+
+	for i := 0; i < transcriptLogUpdateAttempts; i++ {
+		if err := Client.Watch(utils.DefaultContext(), update, keys[1]); err != redis.TxFailedErr {
+			return err
+		}
+	}
+
+	return redis.TxFailedErr
+}
+
+// ApplyMessageUpdate merges a message update event into the message. Returns false if the update did not edit the
+// message, i.e. it only added embeds for links, or was older than the message.
+func ApplyMessageUpdate(msg *message.Message, update message.Message) bool {
+	// Updates without an edited timestamp are embeds being added to links
+	if update.EditedTimestamp == nil {
+		if len(update.Embeds) > 0 {
+			msg.Embeds = update.Embeds
+		}
+
+		return false
+	}
+
+	// Already applied, e.g. if the update was received twice
+	if msg.EditedTimestamp != nil && !update.EditedTimestamp.After(*msg.EditedTimestamp) {
+		return false
+	}
+
+	msg.Content = update.Content
+	msg.Embeds = update.Embeds
+	msg.Attachments = update.Attachments
+	msg.Components = update.Components
+	msg.EditedTimestamp = update.EditedTimestamp
+	return true
+}
+
+// GetLoggedMessage returns the most recent version of the message in the transcript log, or nil if it was not logged.
+// Deletions are ignored, so that the content of deleted messages can still be retrieved.
+func GetLoggedMessage(guildId uint64, ticketId int, messageId uint64) (*message.Message, error) {
+	res, err := Client.HGet(utils.DefaultContext(), buildTranscriptLogMessagesKey(guildId, ticketId), strconv.FormatUint(messageId, 10)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var msg message.Message
+	if err := json.Unmarshal([]byte(res), &msg); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// GetTranscriptLog returns the events in the order they were received
+func GetTranscriptLog(guildId uint64, ticketId int) ([]TranscriptLogEvent, error) {
+	res, err := Client.LRange(utils.DefaultContext(), buildTranscriptLogKey(guildId, ticketId), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]TranscriptLogEvent, len(res))
+	for i, encoded := range res {
+		if err := json.Unmarshal([]byte(encoded), &events[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// SwapMessageDeleteAuditLogCount stores the number of messages that a message delete audit log entry covers, and
+// returns the number that was previously stored, or nil if the entry has not been seen before
+func SwapMessageDeleteAuditLogCount(entryId uint64, count int, expiry time.Duration) (*int, error) {
+	key := fmt.Sprintf("transcriptlog:deleteentry:%d", entryId)
+
+	pipe := Client.TxPipeline()
+	previous := pipe.GetSet(utils.DefaultContext(), key, count)
+	pipe.Expire(utils.DefaultContext(), key, expiry)
+
+	if _, err := pipe.Exec(utils.DefaultContext()); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	res, err := previous.Int()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func DeleteTranscriptLog(guildId uint64, ticketId int) error {
+	return Client.Del(utils.DefaultContext(), buildTranscriptLogKey(guildId, ticketId), buildTranscriptLogMessagesKey(guildId, ticketId)).Err()
+}
+
+func buildTranscriptLogKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("transcriptlog:%d:%d", guildId, ticketId)
+}
+
+func buildTranscriptLogMessagesKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("transcriptlog:messages:%d:%d", guildId, ticketId)
+}
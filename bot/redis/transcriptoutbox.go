@@ -0,0 +1,304 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TranscriptOutboxEntry is a transcript that could not be uploaded to the archiver when the ticket was closed, or whose
+// attachments are still being preserved. The messages are stored separately, so that entries can be listed without
+// loading them.
+type TranscriptOutboxEntry struct {
+	GuildId   uint64    `json:"guild_id,string"`
+	TicketId  int       `json:"ticket_id"`
+	Premium   bool      `json:"premium"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	CreatedAt time.Time `json:"created_at"`
+	// NextAttempt is nil once the entry has run out of attempts, and will only be retried if replayed manually
+	NextAttempt *time.Time `json:"next_attempt,omitempty"`
+	// AttachmentLimit is the number of bytes of attachments that still need to be preserved before the transcript is
+	// uploaded, or zero once they have been
+	AttachmentLimit int `json:"attachment_limit,omitempty"`
+}
+
+// Members are <guild_id>:<ticket_id>, scored by the time of the next attempt. Entries that will not be retried
+// automatically are scored +inf. Entries stay in the schedule until they are deleted, including while they are claimed.
+const transcriptOutboxPendingKey = "transcriptoutbox:pending"
+
+// A claimed entry is rescheduled for when its claim expires, so that it is retried by another worker if the worker that
+// claimed it dies, or fails to save the result
+const transcriptOutboxClaimExpiry = time.Minute * 10
+
+// AddTranscriptOutbox stores the messages of a failed upload, and schedules the first retry
+func AddTranscriptOutbox(entry TranscriptOutboxEntry, messages []message.Message) error {
+	encodedMessages, err := json.Marshal(messages)
+	if err != nil {
+		return err
+	}
+
+	encodedEntry, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	pipe := Client.TxPipeline()
+	pipe.Set(utils.DefaultContext(), buildTranscriptOutboxMessagesKey(entry.GuildId, entry.TicketId), string(encodedMessages), 0)
+	pipe.Set(utils.DefaultContext(), buildTranscriptOutboxKey(entry.GuildId, entry.TicketId), string(encodedEntry), 0)
+	pipe.ZAdd(utils.DefaultContext(), transcriptOutboxPendingKey, &redis.Z{
+		Score:  transcriptOutboxScore(entry.NextAttempt),
+		Member: encodeTranscriptOutboxMember(entry.GuildId, entry.TicketId),
+	})
+
+	_, err = pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+// TakeDueTranscriptOutbox claims and returns every entry that is due to be retried. Each entry is only returned to a
+// single worker, which must call UpdateTranscriptOutbox or DeleteTranscriptOutbox once it has finished with it.
+func TakeDueTranscriptOutbox(now time.Time, limit int64) ([]TranscriptOutboxEntry, error) {
+	members, err := Client.ZRangeByScore(utils.DefaultContext(), transcriptOutboxPendingKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: limit,
+	}).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TranscriptOutboxEntry
+	for _, member := range members {
+		guildId, ticketId, err := decodeTranscriptOutboxMember(member)
+		if err != nil {
+			return entries, err
+		}
+
+		entry, ok, err := ClaimTranscriptOutbox(guildId, ticketId)
+		if err != nil {
+			return entries, err
+		}
+
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// ClaimTranscriptOutbox claims the entry, regardless of when it is due, and returns it. Returns false if the entry does
+// not exist, or another worker is already retrying it.
+func ClaimTranscriptOutbox(guildId uint64, ticketId int) (TranscriptOutboxEntry, bool, error) {
+	claimKey := buildTranscriptOutboxClaimKey(guildId, ticketId)
+
+	claimed, err := Client.SetNX(utils.DefaultContext(), claimKey, 1, transcriptOutboxClaimExpiry).Result()
+	if err != nil {
+		return TranscriptOutboxEntry{}, false, err
+	}
+
+	if !claimed {
+		return TranscriptOutboxEntry{}, false, nil
+	}
+
+	entry, ok, err := GetTranscriptOutbox(guildId, ticketId)
+	if err != nil || !ok {
+		if err := Client.Del(utils.DefaultContext(), claimKey).Err(); err != nil {
+			return entry, false, err
+		}
+
+		return entry, false, err
+	}
+
+	// Only updates the score if the entry has not been deleted since it was read
+	err = Client.ZAddXX(utils.DefaultContext(), transcriptOutboxPendingKey, &redis.Z{
+		Score:  float64(time.Now().Add(transcriptOutboxClaimExpiry).Unix()),
+		Member: encodeTranscriptOutboxMember(guildId, ticketId),
+	}).Err()
+
+	if err != nil {
+		return entry, false, err
+	}
+
+	return entry, true, nil
+}
+
+func GetTranscriptOutbox(guildId uint64, ticketId int) (TranscriptOutboxEntry, bool, error) {
+	var entry TranscriptOutboxEntry
+
+	res, err := Client.Get(utils.DefaultContext(), buildTranscriptOutboxKey(guildId, ticketId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return entry, false, nil
+		}
+
+		return entry, false, err
+	}
+
+	if err := json.Unmarshal([]byte(res), &entry); err != nil {
+		return entry, false, err
+	}
+
+	return entry, true, nil
+}
+
+func GetTranscriptOutboxMessages(guildId uint64, ticketId int) ([]message.Message, error) {
+	res, err := Client.Get(utils.DefaultContext(), buildTranscriptOutboxMessagesKey(guildId, ticketId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []message.Message
+	if err := json.Unmarshal([]byte(res), &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// SetTranscriptOutboxMessages replaces the messages of an entry, e.g. once their attachments have been preserved
+func SetTranscriptOutboxMessages(guildId uint64, ticketId int, messages []message.Message) error {
+	encoded, err := json.Marshal(messages)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildTranscriptOutboxMessagesKey(guildId, ticketId), string(encoded), 0).Err()
+}
+
+// ListTranscriptOutbox returns up to limit entries, soonest to be retried first. If guildId is non-nil, only entries
+// for that guild are returned.
+func ListTranscriptOutbox(guildId *uint64, limit int) ([]TranscriptOutboxEntry, error) {
+	members, err := Client.ZRange(utils.DefaultContext(), transcriptOutboxPendingKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, member := range members {
+		entryGuildId, ticketId, err := decodeTranscriptOutboxMember(member)
+		if err != nil {
+			return nil, err
+		}
+
+		if guildId != nil && *guildId != entryGuildId {
+			continue
+		}
+
+		keys = append(keys, buildTranscriptOutboxKey(entryGuildId, ticketId))
+		if len(keys) >= limit {
+			break
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	res, err := Client.MGet(utils.DefaultContext(), keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]TranscriptOutboxEntry, 0, len(res))
+	for _, raw := range res {
+		// Deleted since the schedule was read
+		encoded, ok := raw.(string)
+		if !ok {
+			continue
+		}
+
+		var entry TranscriptOutboxEntry
+		if err := json.Unmarshal([]byte(encoded), &entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// UpdateTranscriptOutbox saves the entry after a failed attempt, schedules it for entry.NextAttempt, and releases the
+// claim on it
+func UpdateTranscriptOutbox(entry TranscriptOutboxEntry) error {
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	pipe := Client.TxPipeline()
+	pipe.Set(utils.DefaultContext(), buildTranscriptOutboxKey(entry.GuildId, entry.TicketId), string(encoded), 0)
+	pipe.ZAdd(utils.DefaultContext(), transcriptOutboxPendingKey, &redis.Z{
+		Score:  transcriptOutboxScore(entry.NextAttempt),
+		Member: encodeTranscriptOutboxMember(entry.GuildId, entry.TicketId),
+	})
+	pipe.Del(utils.DefaultContext(), buildTranscriptOutboxClaimKey(entry.GuildId, entry.TicketId))
+
+	_, err = pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+func DeleteTranscriptOutbox(guildId uint64, ticketId int) error {
+	pipe := Client.TxPipeline()
+	pipe.ZRem(utils.DefaultContext(), transcriptOutboxPendingKey, encodeTranscriptOutboxMember(guildId, ticketId))
+	pipe.Del(
+		utils.DefaultContext(),
+		buildTranscriptOutboxKey(guildId, ticketId),
+		buildTranscriptOutboxMessagesKey(guildId, ticketId),
+		buildTranscriptOutboxClaimKey(guildId, ticketId),
+	)
+
+	_, err := pipe.Exec(utils.DefaultContext())
+	return err
+}
+
+func transcriptOutboxScore(nextAttempt *time.Time) float64 {
+	if nextAttempt == nil {
+		return math.Inf(1)
+	}
+
+	return float64(nextAttempt.Unix())
+}
+
+func encodeTranscriptOutboxMember(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("%d:%d", guildId, ticketId)
+}
+
+func decodeTranscriptOutboxMember(s string) (uint64, int, error) {
+	split := strings.Split(s, ":")
+	if len(split) != 2 {
+		return 0, 0, fmt.Errorf("invalid transcript outbox member %s", s)
+	}
+
+	guildId, err := strconv.ParseUint(split[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ticketId, err := strconv.Atoi(split[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return guildId, ticketId, nil
+}
+
+func buildTranscriptOutboxKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("transcriptoutbox:%d:%d", guildId, ticketId)
+}
+
+func buildTranscriptOutboxMessagesKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("transcriptoutbox:messages:%d:%d", guildId, ticketId)
+}
+
+func buildTranscriptOutboxClaimKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("transcriptoutbox:claim:%d:%d", guildId, ticketId)
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/common/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// TranscriptViewer is a whitelabel bot's own transcript viewer, which signed transcript links point at instead of the
+// one in the worker's config
+type TranscriptViewer struct {
+	Url        string `json:"url"`
+	SigningKey string `json:"signing_key"`
+}
+
+// GetTranscriptViewer returns nil if the bot does not have its own viewer
+func GetTranscriptViewer(botId uint64) (*TranscriptViewer, error) {
+	res, err := Client.Get(utils.DefaultContext(), buildTranscriptViewerKey(botId)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var viewer TranscriptViewer
+	if err := json.Unmarshal([]byte(res), &viewer); err != nil {
+		return nil, err
+	}
+
+	return &viewer, nil
+}
+
+func SetTranscriptViewer(botId uint64, viewer TranscriptViewer) error {
+	encoded, err := json.Marshal(viewer)
+	if err != nil {
+		return err
+	}
+
+	return Client.Set(utils.DefaultContext(), buildTranscriptViewerKey(botId), string(encoded), 0).Err()
+}
+
+func DeleteTranscriptViewer(botId uint64) error {
+	return Client.Del(utils.DefaultContext(), buildTranscriptViewerKey(botId)).Err()
+}
+
+func buildTranscriptViewerKey(botId uint64) string {
+	return fmt.Sprintf("transcriptviewer:%d", botId)
+}
@@ -0,0 +1,33 @@
+package transcripts
+
+import (
+	"github.com/TicketsBot/archiverclient"
+	v2 "github.com/TicketsBot/logarchiver/model/v2"
+	"github.com/rxdn/gdl/objects/channel/message"
+)
+
+// ArchiverStore stores transcripts in the logarchiver service. The archiver client encrypts transcripts itself.
+type ArchiverStore struct {
+	client archiverclient.ArchiverClient
+}
+
+var _ TranscriptStore = (*ArchiverStore)(nil)
+
+func NewArchiverStore(url string, key []byte) *ArchiverStore {
+	return &ArchiverStore{
+		client: archiverclient.NewArchiverClient(url, key),
+	}
+}
+
+func (s *ArchiverStore) Store(messages []message.Message, guildId uint64, ticketId int, premium bool) error {
+	return s.client.Store(messages, guildId, ticketId, premium)
+}
+
+func (s *ArchiverStore) Get(guildId uint64, ticketId int) (v2.Transcript, error) {
+	transcript, err := s.client.Get(guildId, ticketId)
+	if err == archiverclient.ErrNotFound {
+		return v2.Transcript{}, ErrNotFound
+	}
+
+	return transcript, err
+}
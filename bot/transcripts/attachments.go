@@ -0,0 +1,156 @@
+package transcripts
+
+import (
+	"fmt"
+	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AttachmentStore is implemented by backends that can keep copies of attachments alongside transcripts. Attachments
+// are encrypted with the same key as transcripts, and must be served by the transcript viewer, which checks that the
+// reader can view the transcript before decrypting them.
+type AttachmentStore interface {
+	StoreAttachment(path string, data []byte) error
+}
+
+// Attachments are stored as opaque binary data, so that the storage backend never serves user uploaded HTML or SVG
+const (
+	attachmentStorageContentType = "application/octet-stream"
+	attachmentContentDisposition = "attachment"
+)
+
+// Only these types may be shown inline by the viewer. Anything else must be served as a download.
+var inlineAttachmentContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// AttachmentContentType returns the content type that the viewer should serve a decrypted attachment with, and
+// whether it may be displayed inline. The type is detected from the data, as the type reported by the uploader cannot
+// be trusted.
+func AttachmentContentType(data []byte) (string, bool) {
+	contentType := http.DetectContentType(data)
+	if inlineAttachmentContentTypes[contentType] {
+		return contentType, true
+	}
+
+	return attachmentStorageContentType, false
+}
+
+var attachmentHttpClient = &http.Client{
+	Timeout: time.Second * 30,
+}
+
+var unsafeFilenameCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// AttachmentPath returns the path of a preserved attachment, relative to the root of the store
+func AttachmentPath(guildId uint64, ticketId int, attachment channel.Attachment) string {
+	filename := unsafeFilenameCharacters.ReplaceAllString(attachment.Filename, "_")
+	if filename == "" || strings.Trim(filename, ".") == "" {
+		filename = "attachment"
+	}
+
+	return fmt.Sprintf("attachments/%d/%d/%d/%s", guildId, ticketId, attachment.Id, filename)
+}
+
+// PreserveAttachments downloads the attachments of the messages, in the order they were sent, until limit bytes have
+// been stored, and rewrites their URLs to point at the preserved copies under baseUrl. Attachments that would exceed
+// the limit, or fail to download, keep their original URLs. Returns the number of attachments preserved, and the first
+// error encountered, if any.
+func PreserveAttachments(store AttachmentStore, baseUrl string, guildId uint64, ticketId int, messages []message.Message, limit int) (int, error) {
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+
+	var preserved, stored int
+	var firstErr error
+	for i := range messages {
+		msg := &messages[i]
+		if len(msg.Attachments) == 0 {
+			continue
+		}
+
+		// The slice may be shared with other copies of the message
+		attachments := make([]channel.Attachment, len(msg.Attachments))
+		copy(attachments, msg.Attachments)
+
+		for j, attachment := range attachments {
+			if attachment.Size > limit-stored {
+				continue
+			}
+
+			data, err := downloadAttachment(attachment, limit-stored)
+			if err == nil {
+				err = store.StoreAttachment(AttachmentPath(guildId, ticketId, attachment), data)
+			}
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+
+				continue
+			}
+
+			attachments[j].Url = fmt.Sprintf("%s/%s", baseUrl, AttachmentPath(guildId, ticketId, attachment))
+			attachments[j].ProxyUrl = attachments[j].Url
+
+			stored += len(data)
+			preserved++
+		}
+
+		msg.Attachments = attachments
+	}
+
+	return preserved, firstErr
+}
+
+// Downloads at most maxSize bytes, in case the size reported by Discord is wrong
+func downloadAttachment(attachment channel.Attachment, maxSize int) ([]byte, error) {
+	if !isDiscordCdnUrl(attachment.Url) {
+		return nil, fmt.Errorf("attachment %d is not hosted on the Discord CDN: %s", attachment.Id, attachment.Url)
+	}
+
+	res, err := attachmentHttpClient.Get(attachment.Url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download attachment %d: status %d", attachment.Id, res.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("attachment %d is larger than reported", attachment.Id)
+	}
+
+	return data, nil
+}
+
+// Only attachments on the Discord CDN are downloaded, so that the worker cannot be used to make arbitrary requests
+func isDiscordCdnUrl(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" {
+		return false
+	}
+
+	switch parsed.Hostname() {
+	case "cdn.discordapp.com", "media.discordapp.net":
+		return true
+	default:
+		return false
+	}
+}
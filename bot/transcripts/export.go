@@ -0,0 +1,308 @@
+package transcripts
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/TicketsBot/database"
+	v2 "github.com/TicketsBot/logarchiver/model/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatJson     ExportFormat = "json"
+	ExportFormatMarkdown ExportFormat = "md"
+	ExportFormatText     ExportFormat = "txt"
+)
+
+var ExportFormats = []ExportFormat{
+	ExportFormatJson,
+	ExportFormatMarkdown,
+	ExportFormatText,
+}
+
+// ExportSchemaVersion is the version of the JSON export schema. It is only incremented if a field is removed, renamed or
+// changes meaning; new fields may be added to any object without a new version, so consumers should ignore fields they
+// do not recognise.
+const ExportSchemaVersion = 1
+
+// Export is the JSON export of a ticket transcript. IDs are encoded as strings, as they do not fit in a double. Times
+// are RFC 3339 in UTC. Example:
+//
+//	{
+//	  "schema_version": 1,
+//	  "guild_id": "508392876359680000",
+//	  "ticket_id": 42,
+//	  "opener_id": "217617036749176833",
+//	  "opened_at": "2022-10-01T12:00:00Z",
+//	  "closed_at": "2022-10-01T13:30:00Z",
+//	  "exported_at": "2022-10-02T09:00:00Z",
+//	  "users": [
+//	    {"id": "217617036749176833", "username": "user", "bot": false}
+//	  ],
+//	  "messages": [
+//	    {
+//	      "id": "1026067486035001345",
+//	      "author_id": "217617036749176833",
+//	      "timestamp": "2022-10-01T12:00:05Z",
+//	      "content": "Hello",
+//	      "embeds": [],
+//	      "attachments": []
+//	    }
+//	  ]
+//	}
+type Export struct {
+	SchemaVersion int    `json:"schema_version"`
+	GuildId       uint64 `json:"guild_id,string"`
+	TicketId      int    `json:"ticket_id"`
+	// OpenerId is the user the ticket was opened for
+	OpenerId uint64    `json:"opener_id,string"`
+	OpenedAt time.Time `json:"opened_at"`
+	// ClosedAt is null for tickets closed before close times were recorded
+	ClosedAt   *time.Time `json:"closed_at"`
+	ExportedAt time.Time  `json:"exported_at"`
+	// Users contains every message author, ordered by ID
+	Users []ExportUser `json:"users"`
+	// Messages are in the order they were sent
+	Messages []ExportMessage `json:"messages"`
+}
+
+type ExportUser struct {
+	Id       uint64 `json:"id,string"`
+	Username string `json:"username"`
+	Bot      bool   `json:"bot"`
+}
+
+type ExportMessage struct {
+	Id        uint64    `json:"id,string"`
+	AuthorId  uint64    `json:"author_id,string"`
+	Timestamp time.Time `json:"timestamp"`
+	// Content is the raw message content, with Discord markdown and mentions in the form <@id>
+	Content     string             `json:"content"`
+	Embeds      []ExportEmbed      `json:"embeds"`
+	Attachments []ExportAttachment `json:"attachments"`
+}
+
+// ExportEmbed contains the text of an embed. Empty strings are used for missing fields.
+type ExportEmbed struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Url         string             `json:"url"`
+	Colour      int                `json:"colour"`
+	Author      string             `json:"author"`
+	Footer      string             `json:"footer"`
+	Fields      []ExportEmbedField `json:"fields"`
+}
+
+type ExportEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type ExportAttachment struct {
+	Id       uint64 `json:"id,string"`
+	Filename string `json:"filename"`
+	Url      string `json:"url"`
+	// Size is in bytes
+	Size int `json:"size"`
+}
+
+// NewExport converts a stored transcript into the export schema
+func NewExport(ticket database.Ticket, transcript v2.Transcript) Export {
+	export := Export{
+		SchemaVersion: ExportSchemaVersion,
+		GuildId:       ticket.GuildId,
+		TicketId:      ticket.Id,
+		OpenerId:      ticket.UserId,
+		OpenedAt:      ticket.OpenTime.UTC(),
+		ExportedAt:    time.Now().UTC(),
+		Users:         make([]ExportUser, 0, len(transcript.Entities.Users)),
+		Messages:      make([]ExportMessage, len(transcript.Messages)),
+	}
+
+	if ticket.CloseTime != nil {
+		closedAt := ticket.CloseTime.UTC()
+		export.ClosedAt = &closedAt
+	}
+
+	for _, u := range transcript.Entities.Users {
+		export.Users = append(export.Users, ExportUser{
+			Id:       u.Id,
+			Username: u.Username,
+			Bot:      u.Bot,
+		})
+	}
+
+	sort.Slice(export.Users, func(i, j int) bool {
+		return export.Users[i].Id < export.Users[j].Id
+	})
+
+	for i, msg := range transcript.Messages {
+		exported := ExportMessage{
+			Id:          msg.Id,
+			AuthorId:    msg.AuthorId,
+			Timestamp:   msg.Timestamp.UTC(),
+			Content:     msg.Content,
+			Embeds:      make([]ExportEmbed, len(msg.Embeds)),
+			Attachments: make([]ExportAttachment, len(msg.Attachments)),
+		}
+
+		for j, e := range msg.Embeds {
+			exportedEmbed := ExportEmbed{
+				Title:       e.Title,
+				Description: e.Description,
+				Url:         e.Url,
+				Colour:      e.Color,
+				Fields:      make([]ExportEmbedField, 0, len(e.Fields)),
+			}
+
+			if e.Author != nil {
+				exportedEmbed.Author = e.Author.Name
+			}
+
+			if e.Footer != nil {
+				exportedEmbed.Footer = e.Footer.Text
+			}
+
+			for _, field := range e.Fields {
+				if field != nil {
+					exportedEmbed.Fields = append(exportedEmbed.Fields, ExportEmbedField{
+						Name:   field.Name,
+						Value:  field.Value,
+						Inline: field.Inline,
+					})
+				}
+			}
+
+			exported.Embeds[j] = exportedEmbed
+		}
+
+		for j, attachment := range msg.Attachments {
+			exported.Attachments[j] = ExportAttachment{
+				Id:       attachment.Id,
+				Filename: attachment.Filename,
+				Url:      attachment.Url,
+				Size:     attachment.Size,
+			}
+		}
+
+		export.Messages[i] = exported
+	}
+
+	return export
+}
+
+// Encode renders the export in the given format
+func (e Export) Encode(format ExportFormat) ([]byte, error) {
+	switch format {
+	case ExportFormatJson:
+		return json.MarshalIndent(e, "", "  ")
+	case ExportFormatMarkdown:
+		return []byte(e.markdown()), nil
+	case ExportFormatText:
+		return []byte(e.text()), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %s", format)
+	}
+}
+
+func (e Export) markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Ticket #%d\n\n", e.TicketId))
+	sb.WriteString(fmt.Sprintf("- **Opened by:** %s\n", e.username(e.OpenerId)))
+	sb.WriteString(fmt.Sprintf("- **Opened at:** %s\n", formatExportTime(e.OpenedAt)))
+
+	if e.ClosedAt != nil {
+		sb.WriteString(fmt.Sprintf("- **Closed at:** %s\n", formatExportTime(*e.ClosedAt)))
+	}
+
+	sb.WriteString("\n---\n")
+
+	for _, msg := range e.Messages {
+		sb.WriteString(fmt.Sprintf("\n**%s** — *%s*\n\n", e.username(msg.AuthorId), formatExportTime(msg.Timestamp)))
+
+		if msg.Content != "" {
+			sb.WriteString(msg.Content)
+			sb.WriteString("\n")
+		}
+
+		for _, embed := range msg.Embeds {
+			for _, line := range embed.lines() {
+				sb.WriteString(fmt.Sprintf("> %s\n", line))
+			}
+		}
+
+		for _, attachment := range msg.Attachments {
+			sb.WriteString(fmt.Sprintf("\n[%s](%s)\n", attachment.Filename, attachment.Url))
+		}
+	}
+
+	return sb.String()
+}
+
+func (e Export) text() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Ticket #%d\n", e.TicketId))
+	sb.WriteString(fmt.Sprintf("Opened by: %s\n", e.username(e.OpenerId)))
+	sb.WriteString(fmt.Sprintf("Opened at: %s\n", formatExportTime(e.OpenedAt)))
+
+	if e.ClosedAt != nil {
+		sb.WriteString(fmt.Sprintf("Closed at: %s\n", formatExportTime(*e.ClosedAt)))
+	}
+
+	sb.WriteString("\n")
+
+	for _, msg := range e.Messages {
+		sb.WriteString(fmt.Sprintf("[%s] %s: %s\n", formatExportTime(msg.Timestamp), e.username(msg.AuthorId), msg.Content))
+
+		for _, embed := range msg.Embeds {
+			for _, line := range embed.lines() {
+				sb.WriteString(fmt.Sprintf("    | %s\n", line))
+			}
+		}
+
+		for _, attachment := range msg.Attachments {
+			sb.WriteString(fmt.Sprintf("    Attachment: %s (%s)\n", attachment.Filename, attachment.Url))
+		}
+	}
+
+	return sb.String()
+}
+
+// Returns the text of the embed, one line per element
+func (e ExportEmbed) lines() []string {
+	var lines []string
+	for _, s := range []string{e.Author, e.Title, e.Description} {
+		if s != "" {
+			lines = append(lines, strings.Split(s, "\n")...)
+		}
+	}
+
+	for _, field := range e.Fields {
+		lines = append(lines, fmt.Sprintf("%s: %s", field.Name, strings.ReplaceAll(field.Value, "\n", " ")))
+	}
+
+	if e.Footer != "" {
+		lines = append(lines, e.Footer)
+	}
+
+	return lines
+}
+
+func (e Export) username(userId uint64) string {
+	for _, u := range e.Users {
+		if u.Id == userId {
+			return u.Username
+		}
+	}
+
+	return fmt.Sprintf("Unknown User (%d)", userId)
+}
+
+func formatExportTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
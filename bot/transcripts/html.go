@@ -0,0 +1,262 @@
+package transcripts
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/objects/user"
+	"html/template"
+	"path"
+	"strings"
+	"time"
+)
+
+// HtmlTranscriptData describes the ticket the transcript is for, and the names that mentions are resolved to. Users are
+// resolved from the messages themselves.
+type HtmlTranscriptData struct {
+	GuildName   string
+	GuildIcon   string
+	ChannelName string
+	TicketId    int
+	Roles       map[uint64]string
+	Channels    map[uint64]string
+}
+
+type (
+	htmlTranscript struct {
+		HtmlTranscriptData
+		GeneratedAt time.Time
+		Messages    []htmlMessage
+	}
+
+	htmlMessage struct {
+		Id           uint64
+		AuthorName   string
+		AuthorAvatar string
+		Bot          bool
+		// Consecutive messages from the same author are shown without repeating the header
+		Continuation bool
+		Timestamp    time.Time
+		Edited       bool
+		Reply        *htmlReply
+		Content      template.HTML
+		Attachments  []htmlAttachment
+		Embeds       []htmlEmbed
+	}
+
+	htmlReply struct {
+		Id         uint64
+		AuthorName string
+		Content    string
+	}
+
+	htmlAttachment struct {
+		Filename string
+		Url      string
+		Size     string
+		IsImage  bool
+	}
+
+	htmlEmbed struct {
+		Colour      string
+		AuthorName  string
+		AuthorUrl   string
+		AuthorIcon  string
+		Title       string
+		Url         string
+		Description template.HTML
+		Fields      []htmlEmbedField
+		Image       string
+		Thumbnail   string
+		Footer      string
+		FooterIcon  string
+		Timestamp   *time.Time
+	}
+
+	htmlEmbedField struct {
+		Name   template.HTML
+		Value  template.HTML
+		Inline bool
+	}
+)
+
+// Messages from the same author within this period of each other are grouped together
+const htmlMessageGroupPeriod = time.Minute * 7
+
+//go:embed html.tmpl
+var htmlTemplateSource string
+
+var htmlTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"isoTime": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+	"utcTime": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	// Only used for embed colours, which are formatted by us
+	"safeCSS": func(s string) template.CSS {
+		return template.CSS(s)
+	},
+}).Parse(htmlTemplateSource))
+
+// RenderHtml renders the messages as a standalone HTML page, with styles and scripts inlined, that can be opened
+// without the archiver service. Timestamps are shown in the reader's time zone.
+func RenderHtml(messages []message.Message, data HtmlTranscriptData) ([]byte, error) {
+	r := newMentionResolver(messages, data)
+
+	byId := make(map[uint64]message.Message, len(messages))
+	for _, msg := range messages {
+		byId[msg.Id] = msg
+	}
+
+	transcript := htmlTranscript{
+		HtmlTranscriptData: data,
+		GeneratedAt:        time.Now(),
+		Messages:           make([]htmlMessage, 0, len(messages)),
+	}
+
+	var previous *message.Message
+	for i, msg := range messages {
+		rendered := htmlMessage{
+			Id:           msg.Id,
+			AuthorName:   r.userName(msg.Author.Id, msg.Author.Username),
+			AuthorAvatar: avatarUrl(msg.Author),
+			Bot:          msg.Author.Bot,
+			Timestamp:    msg.Timestamp,
+			Edited:       msg.EditedTimestamp != nil,
+			Content:      r.render(msg.Content),
+			Attachments:  buildHtmlAttachments(msg),
+			Embeds:       buildHtmlEmbeds(msg.Embeds, r),
+		}
+
+		if msg.ReferencedMessage != nil && msg.ReferencedMessage.MessageId != 0 {
+			reply := htmlReply{Id: msg.ReferencedMessage.MessageId}
+			if referenced, ok := byId[reply.Id]; ok {
+				reply.AuthorName = r.userName(referenced.Author.Id, referenced.Author.Username)
+				reply.Content = truncate(r.plain(referenced.Content), 100)
+			}
+
+			rendered.Reply = &reply
+		}
+
+		if previous != nil && rendered.Reply == nil && previous.Author.Id == msg.Author.Id &&
+			msg.Timestamp.Sub(previous.Timestamp) < htmlMessageGroupPeriod {
+			rendered.Continuation = true
+		}
+
+		transcript.Messages = append(transcript.Messages, rendered)
+		previous = &messages[i]
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, transcript); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func buildHtmlAttachments(msg message.Message) []htmlAttachment {
+	attachments := make([]htmlAttachment, len(msg.Attachments))
+	for i, attachment := range msg.Attachments {
+		attachments[i] = htmlAttachment{
+			Filename: attachment.Filename,
+			Url:      attachment.Url,
+			Size:     formatFileSize(attachment.Size),
+			IsImage:  attachment.Width > 0 || isImageFile(attachment.Filename),
+		}
+	}
+
+	return attachments
+}
+
+func buildHtmlEmbeds(embeds []embed.Embed, r *mentionResolver) []htmlEmbed {
+	rendered := make([]htmlEmbed, len(embeds))
+	for i, e := range embeds {
+		out := htmlEmbed{
+			Colour:      fmt.Sprintf("#%06x", e.Color),
+			Title:       e.Title,
+			Url:         e.Url,
+			Description: r.render(e.Description),
+			Timestamp:   e.Timestamp,
+		}
+
+		if e.Color == 0 {
+			out.Colour = "#202225"
+		}
+
+		if e.Author != nil {
+			out.AuthorName = e.Author.Name
+			out.AuthorUrl = e.Author.Url
+			out.AuthorIcon = e.Author.IconUrl
+		}
+
+		if e.Image != nil {
+			out.Image = e.Image.Url
+		}
+
+		if e.Thumbnail != nil {
+			out.Thumbnail = e.Thumbnail.Url
+		}
+
+		if e.Footer != nil {
+			out.Footer = e.Footer.Text
+			out.FooterIcon = e.Footer.IconUrl
+		}
+
+		for _, field := range e.Fields {
+			if field == nil {
+				continue
+			}
+
+			out.Fields = append(out.Fields, htmlEmbedField{
+				Name:   r.render(field.Name),
+				Value:  r.render(field.Value),
+				Inline: field.Inline,
+			})
+		}
+
+		rendered[i] = out
+	}
+
+	return rendered
+}
+
+func avatarUrl(u user.User) string {
+	if url := u.AvatarUrl(64); url != "" {
+		return url
+	}
+
+	return fmt.Sprintf("https://cdn.discordapp.com/embed/avatars/%d.png", int(u.Discriminator)%5)
+}
+
+func isImageFile(filename string) bool {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp":
+		return true
+	default:
+		return false
+	}
+}
+
+func formatFileSize(size int) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.2f MB", float64(size)/1024/1024)
+	case size >= 1024:
+		return fmt.Sprintf("%.2f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d bytes", size)
+	}
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length-1]) + "…"
+}
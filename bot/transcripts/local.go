@@ -0,0 +1,107 @@
+package transcripts
+
+import (
+	"errors"
+	"github.com/TicketsBot/common/encryption"
+	v2 "github.com/TicketsBot/logarchiver/model/v2"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// LocalStore stores transcripts on disk, at <path>/<guild id>/<ticket id>. Intended for self-hosted deployments with a
+// single worker, or a shared volume.
+type LocalStore struct {
+	path string
+	key  []byte
+}
+
+var (
+	_ TranscriptStore = (*LocalStore)(nil)
+	_ AttachmentStore = (*LocalStore)(nil)
+)
+
+func NewLocalStore(path string, key []byte) (*LocalStore, error) {
+	if path == "" {
+		return nil, errors.New("transcript path is not set")
+	}
+
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+
+	return &LocalStore{
+		path: path,
+		key:  key,
+	}, nil
+}
+
+// Store ignores premium, as there is no retention limit on disk
+func (s *LocalStore) Store(messages []message.Message, guildId uint64, ticketId int, _ bool) error {
+	data, err := encodeTranscript(s.key, messages)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.path, strconv.FormatUint(guildId, 10))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a partially written transcript is never read
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.transcriptPath(guildId, ticketId))
+}
+
+func (s *LocalStore) Get(guildId uint64, ticketId int) (v2.Transcript, error) {
+	data, err := os.ReadFile(s.transcriptPath(guildId, ticketId))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return v2.Transcript{}, ErrNotFound
+		}
+
+		return v2.Transcript{}, err
+	}
+
+	return decodeTranscript(s.key, data)
+}
+
+// StoreAttachment writes the encrypted attachment to <path>/<attachment path>, with the same permissions as
+// transcripts, as both are read by the transcript viewer
+func (s *LocalStore) StoreAttachment(path string, data []byte) error {
+	encrypted, err := encryption.Encrypt(s.key, data)
+	if err != nil {
+		return err
+	}
+
+	dest := filepath.Join(s.path, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(dest, encrypted, 0600)
+}
+
+func (s *LocalStore) transcriptPath(guildId uint64, ticketId int) string {
+	return filepath.Join(s.path, strconv.FormatUint(guildId, 10), strconv.Itoa(ticketId))
+}
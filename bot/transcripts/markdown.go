@@ -0,0 +1,223 @@
+package transcripts
+
+import (
+	"fmt"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"html"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resolves mentions to names, and renders the subset of Discord markdown that is commonly used in tickets
+type mentionResolver struct {
+	users    map[uint64]string
+	roles    map[uint64]string
+	channels map[uint64]string
+}
+
+var (
+	// Code and links are matched against the raw content, so that markdown is not applied inside them
+	codeAndLinkRegex = regexp.MustCompile("(?s)```(?:[a-zA-Z0-9_+-]*\n)?(.*?)```|`([^`]+)`|(https?://[^\\s<]+[^\\s<.,:;\"')\\]])")
+
+	// The remaining patterns are matched against escaped content
+	boldRegex          = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	underlineRegex     = regexp.MustCompile(`__(.+?)__`)
+	italicStarRegex    = regexp.MustCompile(`\*(.+?)\*`)
+	italicUnderRegex   = regexp.MustCompile(`\b_(.+?)_\b`)
+	strikethroughRegex = regexp.MustCompile(`~~(.+?)~~`)
+	spoilerRegex       = regexp.MustCompile(`\|\|(.+?)\|\|`)
+	quoteRegex         = regexp.MustCompile(`(?m)^&gt; (.*)$`)
+
+	userMentionRegex    = regexp.MustCompile(`&lt;@!?(\d+)&gt;`)
+	roleMentionRegex    = regexp.MustCompile(`&lt;@&amp;(\d+)&gt;`)
+	channelMentionRegex = regexp.MustCompile(`&lt;#(\d+)&gt;`)
+	emojiRegex          = regexp.MustCompile(`&lt;(a?):(\w+):(\d+)&gt;`)
+	timestampRegex      = regexp.MustCompile(`&lt;t:(-?\d+)(?::([tTdDfFR]))?&gt;`)
+	everyoneRegex       = regexp.MustCompile(`@(everyone|here)`)
+
+	plainMentionRegex = regexp.MustCompile(`<(@!?|@&|#)(\d+)>`)
+)
+
+func newMentionResolver(messages []message.Message, data HtmlTranscriptData) *mentionResolver {
+	r := &mentionResolver{
+		users:    make(map[uint64]string),
+		roles:    data.Roles,
+		channels: data.Channels,
+	}
+
+	for _, msg := range messages {
+		r.addUser(msg.Author.Id, msg.Author.Username, msg.Member.Nick)
+
+		for _, mention := range msg.Mentions {
+			r.addUser(mention.Id, mention.Username, mention.Member.Nick)
+		}
+	}
+
+	return r
+}
+
+func (r *mentionResolver) addUser(id uint64, username, nick string) {
+	if id == 0 {
+		return
+	}
+
+	if nick != "" {
+		r.users[id] = nick
+	} else if _, ok := r.users[id]; !ok && username != "" {
+		r.users[id] = username
+	}
+}
+
+func (r *mentionResolver) userName(id uint64, fallback string) string {
+	if name, ok := r.users[id]; ok {
+		return name
+	}
+
+	if fallback != "" {
+		return fallback
+	}
+
+	return fmt.Sprintf("Unknown User (%d)", id)
+}
+
+func (r *mentionResolver) roleName(id uint64) string {
+	if name, ok := r.roles[id]; ok {
+		return name
+	}
+
+	return "deleted-role"
+}
+
+func (r *mentionResolver) channelName(id uint64) string {
+	if name, ok := r.channels[id]; ok {
+		return name
+	}
+
+	return "deleted-channel"
+}
+
+// render converts the content to HTML. All user content is escaped before any tags are added.
+func (r *mentionResolver) render(content string) template.HTML {
+	if content == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	last := 0
+	for _, match := range codeAndLinkRegex.FindAllStringSubmatchIndex(content, -1) {
+		sb.WriteString(r.renderText(content[last:match[0]]))
+
+		switch {
+		case match[2] != -1:
+			sb.WriteString(fmt.Sprintf("<pre><code>%s</code></pre>", html.EscapeString(content[match[2]:match[3]])))
+		case match[4] != -1:
+			sb.WriteString(fmt.Sprintf("<code>%s</code>", html.EscapeString(content[match[4]:match[5]])))
+		default:
+			link := html.EscapeString(content[match[6]:match[7]])
+			sb.WriteString(fmt.Sprintf(`<a href="%s" target="_blank" rel="noopener noreferrer">%s</a>`, link, link))
+		}
+
+		last = match[1]
+	}
+
+	sb.WriteString(r.renderText(content[last:]))
+
+	return template.HTML(sb.String())
+}
+
+func (r *mentionResolver) renderText(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	s := html.EscapeString(text)
+
+	s = boldRegex.ReplaceAllString(s, "<strong>$1</strong>")
+	s = underlineRegex.ReplaceAllString(s, "<u>$1</u>")
+	s = italicStarRegex.ReplaceAllString(s, "<em>$1</em>")
+	s = italicUnderRegex.ReplaceAllString(s, "<em>$1</em>")
+	s = strikethroughRegex.ReplaceAllString(s, "<s>$1</s>")
+	s = spoilerRegex.ReplaceAllString(s, `<span class="spoiler">$1</span>`)
+	s = quoteRegex.ReplaceAllString(s, "<blockquote>$1</blockquote>")
+	s = everyoneRegex.ReplaceAllString(s, `<span class="mention">@$1</span>`)
+
+	s = roleMentionRegex.ReplaceAllStringFunc(s, func(match string) string {
+		id := parseMentionId(roleMentionRegex, match)
+		return fmt.Sprintf(`<span class="mention">@%s</span>`, html.EscapeString(r.roleName(id)))
+	})
+
+	s = userMentionRegex.ReplaceAllStringFunc(s, func(match string) string {
+		id := parseMentionId(userMentionRegex, match)
+		return fmt.Sprintf(`<span class="mention" title="%d">@%s</span>`, id, html.EscapeString(r.userName(id, "")))
+	})
+
+	s = channelMentionRegex.ReplaceAllStringFunc(s, func(match string) string {
+		id := parseMentionId(channelMentionRegex, match)
+		return fmt.Sprintf(`<span class="mention">#%s</span>`, html.EscapeString(r.channelName(id)))
+	})
+
+	s = emojiRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := emojiRegex.FindStringSubmatch(match)
+
+		extension := "png"
+		if groups[1] == "a" {
+			extension = "gif"
+		}
+
+		return fmt.Sprintf(`<img class="emoji" src="https://cdn.discordapp.com/emojis/%s.%s" alt=":%s:" title=":%s:">`, groups[3], extension, groups[2], groups[2])
+	})
+
+	s = timestampRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := timestampRegex.FindStringSubmatch(match)
+
+		unix, err := strconv.ParseInt(groups[1], 10, 64)
+		if err != nil {
+			return match
+		}
+
+		format := groups[2]
+		if format == "" {
+			format = "f"
+		}
+
+		t := time.Unix(unix, 0).UTC()
+		return fmt.Sprintf(`<time class="timestamp" datetime="%s" data-format="%s">%s</time>`, t.Format(time.RFC3339), format, t.Format("2006-01-02 15:04 UTC"))
+	})
+
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// plain resolves mentions without rendering any markdown, for reply previews
+func (r *mentionResolver) plain(content string) string {
+	return plainMentionRegex.ReplaceAllStringFunc(content, func(match string) string {
+		groups := plainMentionRegex.FindStringSubmatch(match)
+
+		id, err := strconv.ParseUint(groups[2], 10, 64)
+		if err != nil {
+			return match
+		}
+
+		switch groups[1] {
+		case "@&":
+			return "@" + r.roleName(id)
+		case "#":
+			return "#" + r.channelName(id)
+		default:
+			return "@" + r.userName(id, "")
+		}
+	})
+}
+
+func parseMentionId(regex *regexp.Regexp, match string) uint64 {
+	groups := regex.FindStringSubmatch(match)
+	if len(groups) < 2 {
+		return 0
+	}
+
+	id, _ := strconv.ParseUint(groups[1], 10, 64)
+	return id
+}
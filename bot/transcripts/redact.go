@@ -0,0 +1,166 @@
+package transcripts
+
+import (
+	"fmt"
+	"github.com/rxdn/gdl/objects/channel/embed"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"regexp"
+	"strings"
+)
+
+// RedactionPattern matches values that should be removed from transcripts. If Validate is set, matches are only
+// redacted if it returns true, to reduce false positives.
+type RedactionPattern struct {
+	Name     string
+	Regex    *regexp.Regexp
+	Validate func(match string) bool
+}
+
+var builtinRedactionPatterns = map[string]RedactionPattern{
+	"email": {
+		Name:  "email",
+		Regex: regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`),
+	},
+	// Requires a country code, an area code in brackets, or a separator, so that IDs are not matched
+	"phone": {
+		Name:     "phone",
+		Regex:    regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?|\b\d{2,4}[ .-])\d{3,4}[ .-]?\d{3,4}\b`),
+		Validate: validatePhoneNumber,
+	},
+	"card": {
+		Name:     "card",
+		Regex:    regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Validate: validateCardNumber,
+	},
+	// Discord bot tokens, JWTs, and API keys for common services
+	"token": {
+		Name: "token",
+		Regex: regexp.MustCompile(strings.Join([]string{
+			`[MNO][A-Za-z\d_-]{23,25}\.[A-Za-z\d_-]{6}\.[A-Za-z\d_-]{27,38}`,
+			`\beyJ[A-Za-z\d_-]+\.eyJ[A-Za-z\d_-]+\.[A-Za-z\d_-]+`,
+			`\bAKIA[0-9A-Z]{16}\b`,
+			`\bgh[pousr]_[A-Za-z\d]{36,}\b`,
+			`\bxox[abprs]-[A-Za-z\d-]{10,}`,
+			`\b[rs]k_live_[A-Za-z\d]{24,}\b`,
+		}, "|")),
+	},
+	"ip": {
+		Name:  "ip",
+		Regex: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`),
+	},
+}
+
+// BuiltinRedactionPattern returns the built-in detector with the given name
+func BuiltinRedactionPattern(name string) (RedactionPattern, bool) {
+	pattern, ok := builtinRedactionPatterns[name]
+	return pattern, ok
+}
+
+// Redact replaces values matching the patterns in the content and embeds of the messages, modifying them in place.
+// Returns the number of values redacted.
+func Redact(messages []message.Message, patterns []RedactionPattern) int {
+	if len(patterns) == 0 {
+		return 0
+	}
+
+	var count int
+	redact := func(s *string) {
+		for _, pattern := range patterns {
+			*s = pattern.Regex.ReplaceAllStringFunc(*s, func(match string) string {
+				if pattern.Validate != nil && !pattern.Validate(match) {
+					return match
+				}
+
+				count++
+				return fmt.Sprintf("[REDACTED %s]", strings.ToUpper(pattern.Name))
+			})
+		}
+	}
+
+	for i := range messages {
+		msg := &messages[i]
+		redact(&msg.Content)
+
+		for j := range msg.Embeds {
+			e := &msg.Embeds[j]
+			redact(&e.Title)
+			redact(&e.Description)
+
+			if e.Author != nil {
+				author := *e.Author
+				redact(&author.Name)
+				e.Author = &author
+			}
+
+			if e.Footer != nil {
+				footer := *e.Footer
+				redact(&footer.Text)
+				e.Footer = &footer
+			}
+
+			// Fields are pointers, which may be shared with other copies of the message
+			fields := make([]*embed.EmbedField, len(e.Fields))
+			for k, field := range e.Fields {
+				if field == nil {
+					continue
+				}
+
+				copied := *field
+				redact(&copied.Name)
+				redact(&copied.Value)
+				fields[k] = &copied
+			}
+
+			e.Fields = fields
+		}
+	}
+
+	return count
+}
+
+func validatePhoneNumber(match string) bool {
+	digits := countDigits(match)
+	return digits >= 7 && digits <= 15
+}
+
+// Card numbers are 13 to 19 digits, and pass the Luhn check. Unseparated numbers longer than 16 digits are more likely
+// to be Discord IDs.
+func validateCardNumber(match string) bool {
+	digits := countDigits(match)
+	if digits < 13 || digits > 19 || (digits > 16 && digits == len(match)) {
+		return false
+	}
+
+	var sum int
+	double := false
+	for i := len(match) - 1; i >= 0; i-- {
+		c := match[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		n := int(c - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+
+		sum += n
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+func countDigits(s string) int {
+	var count int
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			count++
+		}
+	}
+
+	return count
+}
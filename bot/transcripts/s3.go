@@ -0,0 +1,227 @@
+package transcripts
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/TicketsBot/common/encryption"
+	v2 "github.com/TicketsBot/logarchiver/model/v2"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Store stores transcripts in any service implementing the S3 API, such as AWS S3 or MinIO, under the object key
+// <guild id>/<ticket id>. Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	endpoint   *url.URL
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool
+	key        []byte
+	httpClient *http.Client
+}
+
+var (
+	_ TranscriptStore = (*S3Store)(nil)
+	_ AttachmentStore = (*S3Store)(nil)
+)
+
+// NewS3Store creates a store for the given bucket. pathStyle should be set for services that do not support virtual
+// hosted buckets, such as a local MinIO instance.
+func NewS3Store(endpoint, region, bucket, accessKey, secretKey string, pathStyle bool, key []byte) (*S3Store, error) {
+	if bucket == "" {
+		return nil, errors.New("transcript bucket is not set")
+	}
+
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %s", endpoint)
+	}
+
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &S3Store{
+		endpoint:  parsed,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		pathStyle: pathStyle,
+		key:       key,
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}, nil
+}
+
+// Store ignores premium, as retention should be managed with bucket lifecycle rules
+func (s *S3Store) Store(messages []message.Message, guildId uint64, ticketId int, _ bool) error {
+	data, err := encodeTranscript(s.key, messages)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(http.MethodPut, objectKey(guildId, ticketId), data, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return readS3Error(res)
+	}
+
+	return nil
+}
+
+func (s *S3Store) Get(guildId uint64, ticketId int) (v2.Transcript, error) {
+	res, err := s.do(http.MethodGet, objectKey(guildId, ticketId), nil, nil)
+	if err != nil {
+		return v2.Transcript{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return v2.Transcript{}, ErrNotFound
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return v2.Transcript{}, readS3Error(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return v2.Transcript{}, err
+	}
+
+	return decodeTranscript(s.key, data)
+}
+
+// StoreAttachment stores the encrypted attachment under the object key <attachment path>. The object is stored as a
+// download, in case the bucket is ever made public by mistake.
+func (s *S3Store) StoreAttachment(path string, data []byte) error {
+	encrypted, err := encryption.Encrypt(s.key, data)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{
+		"Content-Type":        attachmentStorageContentType,
+		"Content-Disposition": attachmentContentDisposition,
+	}
+
+	res, err := s.do(http.MethodPut, path, encrypted, headers)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return readS3Error(res)
+	}
+
+	return nil
+}
+
+func (s *S3Store) do(method, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	objectUrl := *s.endpoint
+	if s.pathStyle {
+		objectUrl.Path = fmt.Sprintf("%s/%s/%s", objectUrl.Path, s.bucket, key)
+	} else {
+		objectUrl.Host = fmt.Sprintf("%s.%s", s.bucket, objectUrl.Host)
+		objectUrl.Path = fmt.Sprintf("%s/%s", objectUrl.Path, key)
+	}
+
+	req, err := http.NewRequest(method, objectUrl.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	s.sign(req, body, time.Now().UTC())
+	return s.httpClient.Do(req)
+}
+
+// Signs the request with AWS Signature Version 4
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSha256(signingKey, s.region)
+	signingKey = hmacSha256(signingKey, "s3")
+	signingKey = hmacSha256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func objectKey(guildId uint64, ticketId int) string {
+	return fmt.Sprintf("%d/%d", guildId, ticketId)
+}
+
+func readS3Error(res *http.Response) error {
+	body, _ := ioutil.ReadAll(res.Body)
+	return fmt.Errorf("S3 returned status %d: %s", res.StatusCode, string(body))
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
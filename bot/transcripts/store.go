@@ -0,0 +1,114 @@
+package transcripts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TicketsBot/common/encryption"
+	"github.com/TicketsBot/logarchiver/model"
+	v1 "github.com/TicketsBot/logarchiver/model/v1"
+	v2 "github.com/TicketsBot/logarchiver/model/v2"
+	"github.com/TicketsBot/worker/config"
+	"github.com/rxdn/gdl/objects/channel"
+	"github.com/rxdn/gdl/objects/channel/message"
+	"github.com/rxdn/gdl/objects/guild"
+	"github.com/rxdn/gdl/objects/user"
+)
+
+// TranscriptStore stores the transcripts of closed tickets
+type TranscriptStore interface {
+	Store(messages []message.Message, guildId uint64, ticketId int, premium bool) error
+	Get(guildId uint64, ticketId int) (v2.Transcript, error)
+}
+
+type Backend string
+
+const (
+	BackendArchiver Backend = "archiver"
+	BackendLocal    Backend = "local"
+	BackendS3       Backend = "s3"
+)
+
+var ErrNotFound = errors.New("transcript not found")
+
+// NewStoreFromConfig creates the transcript store for the backend selected in the config. Transcripts are always
+// encrypted with the archiver AES key before leaving the worker.
+func NewStoreFromConfig() (TranscriptStore, error) {
+	key := []byte(config.Conf.Archiver.AesKey)
+
+	switch Backend(config.Conf.Transcripts.Backend) {
+	case BackendArchiver, "":
+		// The archiver has no endpoint for attachments, so they would silently never be preserved
+		if config.Conf.Transcripts.AttachmentUrl != "" {
+			return nil, fmt.Errorf("WORKER_TRANSCRIPT_ATTACHMENT_URL is set, but the archiver backend cannot store attachments")
+		}
+
+		return NewArchiverStore(config.Conf.Archiver.Url, key), nil
+	case BackendLocal:
+		return NewLocalStore(config.Conf.Transcripts.LocalPath, key)
+	case BackendS3:
+		s3 := config.Conf.Transcripts.S3
+		return NewS3Store(s3.Endpoint, s3.Region, s3.Bucket, s3.AccessKey, s3.SecretKey, s3.PathStyle, key)
+	default:
+		return nil, fmt.Errorf("unknown transcript backend %s", config.Conf.Transcripts.Backend)
+	}
+}
+
+// Encodes the messages in the same format as the archiver, so transcripts can be moved between backends
+func encodeTranscript(key []byte, messages []message.Message) ([]byte, error) {
+	transcript := v2.NewTranscript(messages, v2.NoopRetriever[user.User], v2.NoopRetriever[channel.Channel], v2.NoopRetriever[guild.Role])
+
+	data, err := json.Marshal(transcript)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = encryption.Encrypt(key, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.Compress(data), nil
+}
+
+func decodeTranscript(key, data []byte) (v2.Transcript, error) {
+	data, err := encryption.Decompress(data)
+	if err != nil {
+		return v2.Transcript{}, err
+	}
+
+	data, err = encryption.Decrypt(key, data)
+	if err != nil {
+		return v2.Transcript{}, err
+	}
+
+	switch version := model.GetVersion(data); version {
+	case model.V1:
+		var messages []message.Message
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return v2.Transcript{}, err
+		}
+
+		return v1.ConvertToV2(messages), nil
+	case model.V2:
+		var transcript v2.Transcript
+		if err := json.Unmarshal(data, &transcript); err != nil {
+			return v2.Transcript{}, err
+		}
+
+		return transcript, nil
+	default:
+		return v2.Transcript{}, fmt.Errorf("unknown transcript version %d", version)
+	}
+}
+
+// Checks the key is a valid AES-128, AES-192 or AES-256 key, so misconfiguration is caught on startup rather than when
+// the first ticket is closed
+func validateKey(key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	default:
+		return fmt.Errorf("transcript encryption key must be 16, 24 or 32 bytes, got %d", len(key))
+	}
+}
@@ -0,0 +1,45 @@
+package transcripts
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SignTranscriptUrl returns a link to the transcript viewer at baseUrl that can be opened without logging in until
+// expiresAt, in the form:
+//
+//	<base url>/<guild id>/<ticket id>?expires=<unix seconds>&signature=<hex HMAC-SHA256>
+//
+// The viewer must check the link with VerifyTranscriptSignature, using the same key.
+func SignTranscriptUrl(baseUrl string, key []byte, guildId uint64, ticketId int, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+
+	query := url.Values{}
+	query.Set("expires", fmt.Sprint(expires))
+	query.Set("signature", hex.EncodeToString(transcriptSignature(key, guildId, ticketId, expires)))
+
+	return fmt.Sprintf("%s/%d/%d?%s", strings.TrimSuffix(baseUrl, "/"), guildId, ticketId, query.Encode())
+}
+
+// VerifyTranscriptSignature returns true if the signature was produced by SignTranscriptUrl for the ticket, and the
+// link has not expired
+func VerifyTranscriptSignature(key []byte, guildId uint64, ticketId int, expires int64, signature string, now time.Time) bool {
+	if now.Unix() >= expires {
+		return false
+	}
+
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(decoded, transcriptSignature(key, guildId, ticketId, expires))
+}
+
+func transcriptSignature(key []byte, guildId uint64, ticketId int, expires int64) []byte {
+	return hmacSha256(key, fmt.Sprintf("%d:%d:%d", guildId, ticketId, expires))
+}
@@ -1,5 +1,5 @@
 package utils
 
-import "github.com/TicketsBot/archiverclient"
+import "github.com/TicketsBot/worker/bot/transcripts"
 
-var ArchiverClient archiverclient.ArchiverClient
+var TranscriptStore transcripts.TranscriptStore
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	formPlaceholderPattern     = regexp.MustCompile(`%form:([a-z0-9_]+)%`)
+	formPlaceholderInvalidChar = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// FormPlaceholderName returns the name used to refer to a form input's answer in placeholders, e.g. an input labelled
+// "Order ID" can be used as %form:order_id%
+func FormPlaceholderName(label string) string {
+	return strings.Trim(formPlaceholderInvalidChar.ReplaceAllString(strings.ToLower(label), "_"), "_")
+}
+
+// ContainsFormPlaceholder returns whether s contains any %form:name% placeholders
+func ContainsFormPlaceholder(s string) bool {
+	return strings.Contains(s, "%form:")
+}
+
+// ReplaceFormPlaceholders replaces each %form:name% placeholder in s with the result of f
+func ReplaceFormPlaceholders(s string, f func(name string) string) string {
+	return formPlaceholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		return f(formPlaceholderPattern.FindStringSubmatch(placeholder)[1])
+	})
+}
@@ -2,8 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/rxdn/gdl/objects/interaction"
 	"strconv"
+	"strings"
 )
 
 func ButtonInteractionUser(data interaction.MessageComponentInteraction) uint64 {
@@ -36,3 +39,32 @@ func FloatChoice(value float32) interaction.ApplicationCommandOptionChoice {
 		Value: value,
 	}
 }
+
+// AutoCompletePanels suggests the guild's panels whose title contains the value
+func AutoCompletePanels(data interaction.ApplicationCommandAutoCompleteInteraction, value string) []interaction.ApplicationCommandOptionChoice {
+	if data.GuildId.Value == 0 {
+		return nil
+	}
+
+	panels, err := dbclient.Client.Panel.GetByGuild(data.GuildId.Value)
+	if err != nil {
+		sentry.Error(err) // TODO: Context
+		return nil
+	}
+
+	var choices []interaction.ApplicationCommandOptionChoice
+	for _, panel := range panels {
+		if len(choices) >= 25 {
+			break
+		}
+
+		if strings.Contains(strings.ToLower(panel.Title), strings.ToLower(value)) {
+			choices = append(choices, interaction.ApplicationCommandOptionChoice{
+				Name:  panel.Title,
+				Value: panel.PanelId,
+			})
+		}
+	}
+
+	return choices
+}
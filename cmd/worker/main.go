@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
-	"github.com/TicketsBot/archiverclient"
 	"github.com/TicketsBot/common/premium"
 	"github.com/TicketsBot/common/sentry"
+	"github.com/TicketsBot/worker/bot/activity"
 	"github.com/TicketsBot/worker/bot/cache"
 	"github.com/TicketsBot/worker/bot/dbclient"
 	"github.com/TicketsBot/worker/bot/integrations"
@@ -12,6 +12,7 @@ import (
 	"github.com/TicketsBot/worker/bot/metrics/prometheus"
 	"github.com/TicketsBot/worker/bot/metrics/statsd"
 	"github.com/TicketsBot/worker/bot/redis"
+	"github.com/TicketsBot/worker/bot/transcripts"
 	"github.com/TicketsBot/worker/bot/utils"
 	"github.com/TicketsBot/worker/config"
 	"github.com/TicketsBot/worker/event"
@@ -20,6 +21,7 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"time"
+	_ "time/tzdata"
 )
 
 func main() {
@@ -74,7 +76,10 @@ func main() {
 		request.Client.Timeout = time.Second * 30
 	}
 
-	utils.ArchiverClient = archiverclient.NewArchiverClient(config.Conf.Archiver.Url, []byte(config.Conf.Archiver.AesKey))
+	utils.TranscriptStore, err = transcripts.NewStoreFromConfig()
+	if err != nil {
+		panic(err)
+	}
 
 	prometheus.StartServer(config.Conf.Prometheus.Address)
 
@@ -88,9 +93,14 @@ func main() {
 
 	integrations.InitIntegrations()
 
+	activity.Subscribe(activity.PostToLogChannel)
+
 	go messagequeue.ListenTicketClose()
 	go messagequeue.ListenAutoClose()
 	go messagequeue.ListenCloseRequestTimer()
+	go messagequeue.ListenArchiveCleanup()
+	go messagequeue.ListenBulkClose()
+	go messagequeue.ListenTranscriptOutbox()
 
 	fmt.Println("Listening for events...")
 	event.HttpListen(redis.Client, &pgCache)
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"github.com/caarlos0/env/v6"
+	"time"
 )
 
 type Config struct {
@@ -31,6 +33,31 @@ type Config struct {
 		AesKey string `env:"WORKER_ARCHIVER_AES_KEY"`
 	}
 
+	// The AES key used to encrypt transcripts is Archiver.AesKey, regardless of backend
+	Transcripts struct {
+		Backend   string `env:"WORKER_TRANSCRIPT_BACKEND" envDefault:"archiver"` // archiver, local or s3
+		LocalPath string `env:"WORKER_TRANSCRIPT_LOCAL_PATH"`
+		// The URL of the transcript viewer's attachment endpoint. Preserved attachments are encrypted, so the viewer must
+		// check that the reader can view the transcript before decrypting them. Attachments are only preserved if this
+		// is set, and cannot be used with the archiver backend.
+		AttachmentUrl string `env:"WORKER_TRANSCRIPT_ATTACHMENT_URL"`
+		// Links sent to openers are signed with SigningKey, so that they can view their transcript without logging in to
+		// the dashboard. The dashboard link is used instead if either ViewerUrl or SigningKey is unset. Whitelabel bots can
+		// point at their own viewer instead, with /setup transcript-viewer.
+		ViewerUrl  string        `env:"WORKER_TRANSCRIPT_VIEWER_URL"`
+		SigningKey string        `env:"WORKER_TRANSCRIPT_SIGNING_KEY"`
+		LinkExpiry time.Duration `env:"WORKER_TRANSCRIPT_LINK_EXPIRY" envDefault:"168h"` // must be positive
+
+		S3 struct {
+			Endpoint  string `env:"ENDPOINT"`
+			Region    string `env:"REGION"`
+			Bucket    string `env:"BUCKET"`
+			AccessKey string `env:"ACCESS_KEY"`
+			SecretKey string `env:"SECRET_KEY"`
+			PathStyle bool   `env:"PATH_STYLE"`
+		} `envPrefix:"WORKER_TRANSCRIPT_S3_"`
+	}
+
 	WebProxy struct {
 		Url             string `env:"WEB_PROXY_URL"`
 		AuthHeaderName  string `env:"WEB_PROXY_AUTH_HEADER_NAME"`
@@ -80,4 +107,9 @@ func Parse() {
 	if err := env.Parse(&Conf); err != nil {
 		panic(err)
 	}
+
+	// Links would have expired before they were sent
+	if Conf.Transcripts.LinkExpiry <= 0 {
+		panic(fmt.Errorf("WORKER_TRANSCRIPT_LINK_EXPIRY must be positive, got %s", Conf.Transcripts.LinkExpiry))
+	}
 }
@@ -55,6 +55,14 @@ var (
 	TitlePanelSwitched     MessageId = "generic.title.panel_switched"
 	TitleJumpToTop         MessageId = "generic.title.jump_to_top"
 	TitleeReopened         MessageId = "generic.title.reopened"
+	TitleCloseAll          MessageId = "generic.title.close_all"
+	TitleTickets           MessageId = "generic.title.tickets"
+	TitleBusinessHours     MessageId = "generic.title.business_hours"
+	TitleNotEligible       MessageId = "generic.title.not_eligible"
+	TitleLabel             MessageId = "generic.title.label"
+	TitleStaffNotes        MessageId = "generic.title.staff_notes"
+	TitleQueuePosition     MessageId = "generic.title.queue_position"
+	TitleTranscriptExport  MessageId = "generic.title.transcript_export"
 
 	MessageUnknownArgumentType MessageId = "generic.unknown_argument_type"
 
@@ -121,6 +129,39 @@ var (
 	MessageClaimed           MessageId = "commands.claim.success"
 	MessageClaimNoPermission MessageId = "commands.claim.no_permission"
 	MessageClaimThread       MessageId = "commands.claim.thread"
+	MessageAutoAssigned      MessageId = "commands.claim.auto_assigned"
+
+	MessageCloseAllInvalidHours     MessageId = "commands.close_all.invalid_hours"
+	MessageCloseAllInvalidDate      MessageId = "commands.close_all.invalid_date"
+	MessageCloseAllConflictingClaim MessageId = "commands.close_all.conflicting_claim"
+	MessageCloseAllNoTickets        MessageId = "commands.close_all.no_tickets"
+	MessageCloseAllConfirm          MessageId = "commands.close_all.confirm"
+	MessageCloseAllExpired          MessageId = "commands.close_all.expired"
+	MessageCloseAllAlreadyRunning   MessageId = "commands.close_all.already_running"
+	MessageCloseAllStarted          MessageId = "commands.close_all.started"
+	MessageCloseAllProgress         MessageId = "commands.close_all.progress"
+	MessageCloseAllComplete         MessageId = "commands.close_all.complete"
+
+	MessageTicketListEmpty           MessageId = "commands.tickets.list.empty"
+	MessageTicketListPage            MessageId = "commands.tickets.list.page"
+	MessageTicketListPrevious        MessageId = "commands.tickets.list.previous"
+	MessageTicketListNext            MessageId = "commands.tickets.list.next"
+	MessageTicketListOpen            MessageId = "commands.tickets.list.open"
+	MessageTicketListClosed          MessageId = "commands.tickets.list.closed"
+	MessageTicketListTranscript      MessageId = "commands.tickets.list.transcript"
+	MessageTicketListNoTranscript    MessageId = "commands.tickets.list.no_transcript"
+	MessageTicketListExpired         MessageId = "commands.tickets.list.expired"
+	MessageTicketSearchInvalidTicket MessageId = "commands.tickets.search.invalid_ticket"
+	MessageTicketSearchInvalidDate   MessageId = "commands.tickets.search.invalid_date"
+
+	MessageBusinessHoursClosed             MessageId = "commands.open.business_hours.closed"
+	MessageBusinessHoursClosedIndefinitely MessageId = "commands.open.business_hours.closed_indefinitely"
+
+	MessageEligibilityRequiredRole  MessageId = "commands.open.eligibility.required_role"
+	MessageEligibilityForbiddenRole MessageId = "commands.open.eligibility.forbidden_role"
+	MessageEligibilityAccountAge    MessageId = "commands.open.eligibility.account_age"
+	MessageEligibilityMemberAge     MessageId = "commands.open.eligibility.member_age"
+	MessageEligibilityBloxlink      MessageId = "commands.open.eligibility.bloxlink"
 
 	MessagePanel MessageId = "commands.panel"
 
@@ -175,6 +216,8 @@ var (
 	MessageInvalidUser       MessageId = "generic.invalid_user"
 
 	MessageTicketLimitReached       MessageId = "commands.open.ticket_limit"
+	MessagePanelTicketLimitReached  MessageId = "commands.open.panel_ticket_limit"
+	MessageTicketDuplicate          MessageId = "commands.open.duplicate"
 	MessageTooManyTickets           MessageId = "commands.open.too_many_tickets"
 	MessageGuildChannelLimitReached MessageId = "commands.open.guild_channel_limit"
 	MessageTicketStartedFrom        MessageId = "commands.open.from"
@@ -182,6 +225,44 @@ var (
 	MessageFormMissingInput         MessageId = "commands.open.missing_form_answer"
 	MessageOpenCommandDisabled      MessageId = "commands.open.disabled"
 	MessageOpenCantSeeParentChannel MessageId = "commands.open.threads.cant_see_parent_channel"
+	MessageOpenedOnBehalf           MessageId = "commands.open.on_behalf"
+	MessageOpenedOnBehalfDM         MessageId = "commands.open.on_behalf.dm"
+	MessageOpenOnBehalfInvalidUser  MessageId = "commands.open.on_behalf.invalid_user"
+
+	MessageLabelInvalid      MessageId = "commands.label.invalid"
+	MessageLabelLimitReached MessageId = "commands.label.limit_reached"
+	MessageLabelAdded        MessageId = "commands.label.added"
+	MessageLabelRemoved      MessageId = "commands.label.removed"
+	MessageLabelNotApplied   MessageId = "commands.label.not_applied"
+	MessageLabelNotFound     MessageId = "commands.label.not_found"
+	MessageLabelDeleted      MessageId = "commands.label.deleted"
+
+	MessageNoteAdded            MessageId = "commands.note.added"
+	MessageStaffNotesDisabled   MessageId = "commands.note.disabled"
+	MessageStaffNotesThreadMode MessageId = "commands.note.thread_mode"
+
+	MessageQueuePositionUpdate MessageId = "commands.open.queue_position_update"
+
+	MessageTranscriptExportInvalidFormat MessageId = "commands.transcript.export.invalid_format"
+	MessageTranscriptExportNotFound      MessageId = "commands.transcript.export.not_found"
+	MessageTranscriptExportNoPermission  MessageId = "commands.transcript.export.no_permission"
+	MessageTranscriptExportDmsDisabled   MessageId = "commands.transcript.export.dms_disabled"
+	MessageTranscriptExportDm            MessageId = "commands.transcript.export.dm"
+	MessageTranscriptExportSuccess       MessageId = "commands.transcript.export.success"
+
+	MessageFormValidationTooShort      MessageId = "commands.open.form_validation.too_short"
+	MessageFormValidationTooLong       MessageId = "commands.open.form_validation.too_long"
+	MessageFormValidationPattern       MessageId = "commands.open.form_validation.pattern"
+	MessageFormValidationNumber        MessageId = "commands.open.form_validation.number"
+	MessageFormValidationNumberTooLow  MessageId = "commands.open.form_validation.number_too_low"
+	MessageFormValidationNumberTooHigh MessageId = "commands.open.form_validation.number_too_high"
+	MessageFormValidationEmail         MessageId = "commands.open.form_validation.email"
+	MessageFormValidationUrl           MessageId = "commands.open.form_validation.url"
+	MessageFormValidationDiscordId     MessageId = "commands.open.form_validation.discord_id"
+	MessageFormValidationRetry         MessageId = "commands.open.form_validation.retry"
+	MessageFormValidationExpired       MessageId = "commands.open.form_validation.expired"
+	MessageFormContinue                MessageId = "commands.open.form_continue"
+	MessageFormContinueButton          MessageId = "commands.open.form_continue.button"
 
 	MessageCloseRequestNoReason     MessageId = "commands.close_request.no_reason"
 	MessageCloseRequestWithReason   MessageId = "commands.close_request.with_reason"
@@ -227,9 +308,11 @@ var (
 	MessageAlreadyJoinedThread    MessageId = "button.join_thread.already_joined"
 	MessageJoinThreadSuccess      MessageId = "button.join_thread.success"
 
-	HelpAdminCheckPremium MessageId = "help.admin.check_premium"
-	HelpAdminBlacklist    MessageId = "help.admin.blacklist"
-	HelpAdminUnblacklist  MessageId = "help.admin.unblacklist"
+	HelpAdminCheckPremium     MessageId = "help.admin.check_premium"
+	HelpAdminBlacklist        MessageId = "help.admin.blacklist"
+	HelpAdminUnblacklist      MessageId = "help.admin.unblacklist"
+	HelpAdminReplayTranscript MessageId = "help.admin.replay_transcript"
+	HelpAdminTranscriptOutbox MessageId = "help.admin.transcript_outbox"
 
 	SetupChoose                    MessageId = "setup.info.choose"
 	SetupAutoDescription           MessageId = "setup.info.auto"
@@ -271,6 +354,83 @@ var (
 	SetupThreadsSuccess                 MessageId = "setup.threads.success"
 	SetupThreadsDisabled                MessageId = "setup.threads.disabled"
 
+	SetupArchiveCategoryInvalid   MessageId = "setup.archive_category.invalid"
+	SetupArchiveCategoryRetention MessageId = "setup.archive_category.retention"
+	SetupArchiveCategorySuccess   MessageId = "setup.archive_category.success"
+	SetupArchiveCategoryDisabled  MessageId = "setup.archive_category.disabled"
+
+	SetupAutoAssignInvalidStrategy MessageId = "setup.auto_assign.invalid_strategy"
+	SetupAutoAssignInvalidCap      MessageId = "setup.auto_assign.invalid_cap"
+	SetupAutoAssignSuccess         MessageId = "setup.auto_assign.success"
+	SetupAutoAssignDisabled        MessageId = "setup.auto_assign.disabled"
+
+	SetupBusinessHoursInvalidTimezone MessageId = "setup.business_hours.invalid_timezone"
+	SetupBusinessHoursInvalidSchedule MessageId = "setup.business_hours.invalid_schedule"
+	SetupBusinessHoursInvalidHolidays MessageId = "setup.business_hours.invalid_holidays"
+	SetupBusinessHoursInvalidMode     MessageId = "setup.business_hours.invalid_mode"
+	SetupBusinessHoursInvalidMessage  MessageId = "setup.business_hours.invalid_message"
+	SetupBusinessHoursSuccess         MessageId = "setup.business_hours.success"
+	SetupBusinessHoursDisabled        MessageId = "setup.business_hours.disabled"
+
+	SetupPanelRequirementsInvalidRoles MessageId = "setup.panel_requirements.invalid_roles"
+	SetupPanelRequirementsInvalidDays  MessageId = "setup.panel_requirements.invalid_days"
+	SetupPanelRequirementsSuccess      MessageId = "setup.panel_requirements.success"
+	SetupPanelRequirementsDisabled     MessageId = "setup.panel_requirements.disabled"
+
+	SetupTicketLimitsInvalidTarget MessageId = "setup.ticket_limits.invalid_target"
+	SetupTicketLimitsInvalid       MessageId = "setup.ticket_limits.invalid"
+	SetupTicketLimitsPanel         MessageId = "setup.ticket_limits.panel"
+	SetupTicketLimitsRole          MessageId = "setup.ticket_limits.role"
+	SetupTicketLimitsPanelRemoved  MessageId = "setup.ticket_limits.panel_removed"
+	SetupTicketLimitsRoleRemoved   MessageId = "setup.ticket_limits.role_removed"
+	SetupTicketLimitsDuplicates    MessageId = "setup.ticket_limits.duplicates"
+
+	SetupFormValidationInvalidInput   MessageId = "setup.form_validation.invalid_input"
+	SetupFormValidationInvalidLength  MessageId = "setup.form_validation.invalid_length"
+	SetupFormValidationInvalidPattern MessageId = "setup.form_validation.invalid_pattern"
+	SetupFormValidationInvalidFormat  MessageId = "setup.form_validation.invalid_format"
+	SetupFormValidationInvalidRange   MessageId = "setup.form_validation.invalid_range"
+	SetupFormValidationSuccess        MessageId = "setup.form_validation.success"
+	SetupFormValidationDisabled       MessageId = "setup.form_validation.disabled"
+
+	SetupPanelLabelsInvalid MessageId = "setup.panel_labels.invalid"
+	SetupPanelLabelsSuccess MessageId = "setup.panel_labels.success"
+	SetupPanelLabelsCleared MessageId = "setup.panel_labels.cleared"
+
+	SetupStaffNotesInvalidMode     MessageId = "setup.staff_notes.invalid_mode"
+	SetupStaffNotesInvalidCategory MessageId = "setup.staff_notes.invalid_category"
+	SetupStaffNotesSuccess         MessageId = "setup.staff_notes.success"
+	SetupStaffNotesDisabled        MessageId = "setup.staff_notes.disabled"
+
+	SetupHtmlTranscriptsSuccess  MessageId = "setup.html_transcripts.success"
+	SetupHtmlTranscriptsDisabled MessageId = "setup.html_transcripts.disabled"
+
+	SetupRedactionInvalidDetector    MessageId = "setup.redaction.invalid_detector"
+	SetupRedactionEnabled            MessageId = "setup.redaction.enabled"
+	SetupRedactionDisabled           MessageId = "setup.redaction.disabled"
+	SetupRedactionRuleInvalidName    MessageId = "setup.redaction_rule.invalid_name"
+	SetupRedactionRuleInvalidPattern MessageId = "setup.redaction_rule.invalid_pattern"
+	SetupRedactionRuleLimit          MessageId = "setup.redaction_rule.limit"
+	SetupRedactionRuleAdded          MessageId = "setup.redaction_rule.added"
+	SetupRedactionRuleRemoved        MessageId = "setup.redaction_rule.removed"
+	SetupRedactionRuleNotFound       MessageId = "setup.redaction_rule.not_found"
+
+	SetupActivityLogInvalidChannel MessageId = "setup.activity_log.invalid_channel"
+	SetupActivityLogSuccess        MessageId = "setup.activity_log.success"
+	SetupActivityLogDisabled       MessageId = "setup.activity_log.disabled"
+	SetupActivityLogInvalidEvent   MessageId = "setup.activity_log_event.invalid_event"
+	SetupActivityLogEventEnabled   MessageId = "setup.activity_log_event.enabled"
+	SetupActivityLogEventDisabled  MessageId = "setup.activity_log_event.disabled"
+
+	SetupMessageLogInvalidChannel MessageId = "setup.message_log.invalid_channel"
+	SetupMessageLogSuccess        MessageId = "setup.message_log.success"
+	SetupMessageLogDisabled       MessageId = "setup.message_log.disabled"
+
+	SetupTranscriptViewerNotWhitelabel MessageId = "setup.transcript_viewer.not_whitelabel"
+	SetupTranscriptViewerInvalid       MessageId = "setup.transcript_viewer.invalid"
+	SetupTranscriptViewerSuccess       MessageId = "setup.transcript_viewer.success"
+	SetupTranscriptViewerRemoved       MessageId = "setup.transcript_viewer.removed"
+
 	MessageOwnerIsAlreadyAdmin MessageId = "commands.addadmin.owner"
 	MessageHelpInvite          MessageId = "help.invite"
 	MessageInvite              MessageId = "commands.invite"
@@ -310,6 +470,17 @@ var (
 	HelpClaim              MessageId = "help.claim"
 	HelpClose              MessageId = "help.close"
 	HelpCloseRequest       MessageId = "help.close_request"
+	HelpCloseAll           MessageId = "help.close_all"
+	HelpTickets            MessageId = "help.tickets"
+	HelpTicketsList        MessageId = "help.tickets.list"
+	HelpTicketsSearch      MessageId = "help.tickets.search"
+	HelpLabel              MessageId = "help.label"
+	HelpLabelAdd           MessageId = "help.label.add"
+	HelpLabelRemove        MessageId = "help.label.remove"
+	HelpLabelDelete        MessageId = "help.label.delete"
+	HelpNote               MessageId = "help.note"
+	HelpTranscript         MessageId = "help.transcript"
+	HelpTranscriptExport   MessageId = "help.transcript.export"
 	HelpOpen               MessageId = "help.open"
 	HelpRemove             MessageId = "help.remove"
 	HelpRename             MessageId = "help.rename"